@@ -0,0 +1,101 @@
+package backup
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/minepkg/minepkg/internals/backup"
+	"github.com/minepkg/minepkg/internals/globals"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// defaultKeep is the number of backups per world that are kept if "backupKeep" is not set
+const defaultKeep = 10
+
+var logger = globals.Logger
+
+var SubCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Create, restore and rotate world backups",
+	Long: `
+Backs up the savegames of this instance (or the server world with --server).
+Backups are stored in the "backups" directory of the instance.
+
+The retention policy used by "minepkg backup prune" can be configured with:
+  minepkg config set backupKeep 10     # backups to keep per world
+  minepkg config set backupMaxAge 720h # remove backups older than this
+
+Servers can be backed up automatically:
+  minepkg config set backupOnLaunch true  # before every "minepkg launch --server"
+  minepkg config set backupInterval 6h    # while the server is running
+`,
+}
+
+// Retention returns the configured retention policy
+func Retention() (backup.Retention, error) {
+	retention := backup.Retention{Keep: defaultKeep}
+	if viper.IsSet("backupKeep") {
+		retention.Keep = viper.GetInt("backupKeep")
+	}
+	if maxAge := viper.GetString("backupMaxAge"); maxAge != "" {
+		d, err := time.ParseDuration(maxAge)
+		if err != nil {
+			return retention, fmt.Errorf("invalid backupMaxAge %q: %w", maxAge, err)
+		}
+		retention.MaxAge = d
+	}
+	return retention, nil
+}
+
+// Interval returns the configured interval for automatic server backups or 0 if disabled
+func Interval() (time.Duration, error) {
+	interval := viper.GetString("backupInterval")
+	if interval == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(interval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid backupInterval %q: %w", interval, err)
+	}
+	return d, nil
+}
+
+// worldsDir returns the directory that contains the worlds
+func worldsDir(instance *instances.Instance, server bool) string {
+	if server {
+		return instance.McDir()
+	}
+	return instance.SavesDir()
+}
+
+// Worlds backs up the server world (or all client savegames) of the instance and prunes old backups
+func Worlds(instance *instances.Instance, server bool) ([]*backup.Backup, error) {
+	retention, err := Retention()
+	if err != nil {
+		return nil, err
+	}
+	worlds, err := instance.WorldDirs(server)
+	if err != nil {
+		return nil, err
+	}
+
+	created := make([]*backup.Backup, 0, len(worlds))
+	for _, world := range worlds {
+		b, err := backup.Create(world, instance.BackupsDir())
+		if err != nil {
+			return created, err
+		}
+		created = append(created, b)
+	}
+
+	if _, err := backup.Prune(instance.BackupsDir(), retention); err != nil {
+		return created, err
+	}
+	return created, nil
+}
+
+func humanSize(size int64) string {
+	return fmt.Sprintf("%.1f MiB", float64(size)/(1024*1024))
+}
@@ -0,0 +1,46 @@
+package backup
+
+import (
+	"fmt"
+
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	runner := &createRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:   "create",
+		Short: "Backs up all savegames (or the server world)",
+		Args:  cobra.ExactArgs(0),
+	}, runner)
+
+	cmd.Flags().BoolVarP(&runner.server, "server", "s", false, "Back up the server world")
+
+	SubCmd.AddCommand(cmd.Command)
+}
+
+type createRunner struct {
+	server bool
+}
+
+func (c *createRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := instances.NewFromWd()
+	if err != nil {
+		return err
+	}
+
+	created, err := Worlds(instance, c.server)
+	if err != nil {
+		return err
+	}
+	if len(created) == 0 {
+		logger.Info("No worlds to back up")
+		return nil
+	}
+	for _, b := range created {
+		logger.Info(fmt.Sprintf(" ✓ Created %s (%s)", b.Name(), humanSize(b.Size)))
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package backup
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/minepkg/minepkg/internals/backup"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	SubCmd.AddCommand(commands.New(&cobra.Command{
+		Use:     "list",
+		Short:   "Lists all backups of this instance",
+		Aliases: []string{"ls"},
+		Args:    cobra.ExactArgs(0),
+	}, &listRunner{}).Command)
+}
+
+type listRunner struct{}
+
+func (l *listRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := instances.NewFromWd()
+	if err != nil {
+		return err
+	}
+
+	backups, err := backup.List(instance.BackupsDir())
+	if err != nil {
+		return err
+	}
+	if len(backups) == 0 {
+		fmt.Println("No backups yet. Create one with \"minepkg backup create\"")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tWORLD\tCREATED\tSIZE")
+	for _, b := range backups {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", b.Name(), b.World, b.Created.Format("2006-01-02 15:04:05"), humanSize(b.Size))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,62 @@
+package backup
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/minepkg/minepkg/internals/backup"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	runner := &pruneRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:   "prune",
+		Short: "Removes backups according to the retention policy",
+		Long: `
+Removes old backups. The latest backup of each world is always kept.
+Defaults to the configured "backupKeep" and "backupMaxAge".
+`,
+		Args: cobra.ExactArgs(0),
+	}, runner)
+
+	cmd.Flags().IntVar(&runner.keep, "keep", -1, "Number of backups to keep per world (overwrites backupKeep)")
+	cmd.Flags().DurationVar(&runner.maxAge, "max-age", 0, "Remove backups older than this (eg. 720h, overwrites backupMaxAge)")
+
+	SubCmd.AddCommand(cmd.Command)
+}
+
+type pruneRunner struct {
+	keep   int
+	maxAge time.Duration
+}
+
+func (p *pruneRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := instances.NewFromWd()
+	if err != nil {
+		return err
+	}
+
+	retention, err := Retention()
+	if err != nil {
+		return err
+	}
+	if p.keep >= 0 {
+		retention.Keep = p.keep
+	}
+	if p.maxAge != 0 {
+		retention.MaxAge = p.maxAge
+	}
+
+	removed, err := backup.Prune(instance.BackupsDir(), retention)
+	if err != nil {
+		return err
+	}
+	for _, b := range removed {
+		fmt.Println("removed " + b.Name())
+	}
+	logger.Info(fmt.Sprintf(" ✓ Removed %d backups", len(removed)))
+	return nil
+}
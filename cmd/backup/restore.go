@@ -0,0 +1,98 @@
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jwalton/gchalk"
+	"github.com/manifoldco/promptui"
+	"github.com/minepkg/minepkg/internals/backup"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	runner := &restoreRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:   "restore <backup|world>",
+		Short: "Replaces a world with a backup",
+		Long: `
+Replaces a world with the given backup. If a world name is given, its latest backup is restored.
+The current world is backed up first. Restoring is aborted if that backup fails (unless --force is used).
+`,
+		Args: cobra.ExactArgs(1),
+	}, runner)
+
+	cmd.Flags().BoolVarP(&runner.server, "server", "s", false, "Restore the server world")
+	cmd.Flags().BoolVarP(&runner.yes, "yes", "y", false, "Do not ask for confirmation")
+	cmd.Flags().BoolVarP(&runner.force, "force", "f", false, "Restore even if the current world could not be backed up")
+
+	SubCmd.AddCommand(cmd.Command)
+}
+
+type restoreRunner struct {
+	server bool
+	yes    bool
+	force  bool
+}
+
+func (r *restoreRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := instances.NewFromWd()
+	if err != nil {
+		return err
+	}
+
+	b, err := backup.Find(instance.BackupsDir(), args[0])
+	if err != nil {
+		if errors.Is(err, backup.ErrNotFound) {
+			return &commands.CliError{
+				Text: fmt.Sprintf("backup %s does not exist", args[0]),
+				Suggestions: []string{
+					fmt.Sprintf("List all backups with %s", gchalk.Bold("minepkg backup list")),
+				},
+			}
+		}
+		return err
+	}
+
+	if !r.yes && !viper.GetBool("nonInteractive") {
+		prompt := promptui.Prompt{
+			Label:     fmt.Sprintf("Replace %s with %s", b.World, b.Name()),
+			IsConfirm: true,
+		}
+		if _, err := prompt.Run(); err != nil {
+			fmt.Println("Aborting")
+			return nil
+		}
+	}
+
+	// the current world is backed up, so restoring can be undone
+	dir := worldsDir(instance, r.server)
+	worldDir := filepath.Join(dir, b.World)
+	if _, err := os.Stat(worldDir); err == nil {
+		safety, err := backup.Create(worldDir, instance.BackupsDir())
+		switch {
+		case err == nil:
+			logger.Info(" ✓ Backed up the current world as " + safety.Name())
+		case r.force:
+			logger.Warn("Could not back up the current world: " + err.Error())
+		default:
+			return &commands.CliError{
+				Text: "could not back up the current world: " + err.Error(),
+				Suggestions: []string{
+					fmt.Sprintf("Use %s to restore without backing up the current world", gchalk.Bold("--force")),
+				},
+			}
+		}
+	}
+
+	if err := backup.Restore(b, dir); err != nil {
+		return err
+	}
+	logger.Info(" ✓ Restored " + b.Name())
+	return nil
+}
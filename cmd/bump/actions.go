@@ -101,7 +101,7 @@ func (b *bumpRunner) gitPushAction() *action {
 	return action
 }
 
-func (b *bumpRunner) gradleAction() *action {
+func (b *bumpRunner) gradleAction(propsPath string) *action {
 	action := &action{
 		enabled:      true,
 		reason:       "",
@@ -110,7 +110,7 @@ func (b *bumpRunner) gradleAction() *action {
 		successText:  "updated gradle.properties",
 	}
 
-	props, err := gradleCheck()
+	props, err := gradleCheck(propsPath)
 	if err != nil {
 		action.enabled = false
 		action.reason = err.Error()
@@ -126,7 +126,7 @@ func (b *bumpRunner) gradleAction() *action {
 	// looks good, set run function
 	action.run = func() error {
 		props.Set("mod_version", b.targetVersion)
-		f, err := os.Create("./gradle.properties")
+		f, err := os.Create(propsPath)
 		if err != nil {
 			return err
 		}
@@ -138,8 +138,8 @@ func (b *bumpRunner) gradleAction() *action {
 	return action
 }
 
-func gradleCheck() (*properties.Properties, error) {
-	props, err := properties.LoadFile("./gradle.properties", properties.UTF8)
+func gradleCheck(propsPath string) (*properties.Properties, error) {
+	props, err := properties.LoadFile(propsPath, properties.UTF8)
 	if err != nil {
 		return props, fmt.Errorf("gradle.properties does not exist " + err.Error())
 	}
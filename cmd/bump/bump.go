@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -108,7 +109,7 @@ func (b *bumpRunner) RunE(cmd *cobra.Command, args []string) error {
 	actions := []*action{}
 
 	if instance.Manifest.Package.Type == "mod" {
-		actions = append(actions, b.gradleAction())
+		actions = append(actions, b.gradleAction(filepath.Join(instance.Directory, "gradle.properties")))
 	}
 
 	if !b.noGit {
@@ -0,0 +1,10 @@
+package bundle
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var SubCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Create and install offline bundles for air-gapped machines",
+}
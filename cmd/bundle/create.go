@@ -0,0 +1,93 @@
+package bundle
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/minepkg/minepkg/internals/bundle"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/globals"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/minepkg/minepkg/internals/launcher"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	runner := &createRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:   "create",
+		Short: "Writes a bundle containing everything needed to launch this instance offline",
+		Long: `
+Writes a single zip file containing the manifest, lockfile, overwrites, every locked package,
+the launch manifests, libraries, assets and optionally the Java runtime.
+Use "minepkg bundle install" on the target machine to install it.
+`,
+		Args: cobra.ExactArgs(0),
+	}, runner)
+
+	cmd.Flags().StringVarP(&runner.output, "output", "o", "", "Output file (default is <package-name>.bundle.zip)")
+	cmd.Flags().BoolVar(&runner.java, "java", false, "Include the Java runtime")
+	cmd.Flags().BoolVarP(&runner.server, "server", "s", false, "Bundle for a server (skips the client assets)")
+
+	SubCmd.AddCommand(cmd.Command)
+}
+
+type createRunner struct {
+	output string
+	java   bool
+	server bool
+}
+
+func (c *createRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := instances.NewFromWd()
+	if err != nil {
+		return err
+	}
+	instance.MinepkgAPI = globals.ApiClient
+
+	cliLauncher := launcher.Launcher{
+		Instance:       instance,
+		ServerMode:     c.server,
+		NonInteractive: viper.GetBool("nonInteractive"),
+		UseSystemJava:  !c.java,
+	}
+
+	// makes sure everything is downloaded
+	if err := cliLauncher.Prepare(); err != nil {
+		return err
+	}
+
+	opts := &bundle.Options{Server: c.server}
+	if c.java {
+		java, err := cliLauncher.Java(context.TODO())
+		if err != nil {
+			return err
+		}
+		opts.JavaDir = java.Dir()
+	}
+
+	output := c.output
+	if output == "" {
+		output = instance.Manifest.Package.Name + ".bundle.zip"
+	}
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fmt.Println("\nWriting bundle to " + output)
+	if err := bundle.Create(f, instance, cliLauncher.LaunchManifest, opts); err != nil {
+		os.Remove(output)
+		return err
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Bundle created (%d MiB)\n", stat.Size()/(1024*1024))
+	return nil
+}
@@ -0,0 +1,69 @@
+package bundle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/bundle"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	runner := &installRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:   "install <file> [directory]",
+		Short: "Installs a bundle created with \"minepkg bundle create\"",
+		Long: `
+Seeds the local caches with the content of the bundle and writes the manifest,
+lockfile & overwrites to the given directory (defaults to the current directory).
+`,
+		Args: cobra.RangeArgs(1, 2),
+	}, runner)
+
+	cmd.Flags().BoolVarP(&runner.force, "force", "f", false, "Overwrite an existing minepkg.toml")
+
+	SubCmd.AddCommand(cmd.Command)
+}
+
+type installRunner struct {
+	force bool
+}
+
+func (i *installRunner) RunE(cmd *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) == 2 {
+		dir = args[1]
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "minepkg.toml")); err == nil && !i.force {
+		return &commands.CliError{
+			Text: "this directory already contains a minepkg.toml",
+			Suggestions: []string{
+				"Install the bundle into an empty directory",
+				fmt.Sprintf("Overwrite it with %s", gchalk.Bold("--force")),
+			},
+		}
+	}
+
+	userCache, err := os.UserCacheDir()
+	if err != nil {
+		return err
+	}
+
+	result, err := bundle.Install(args[0], filepath.Join(userCache, "minepkg"), dir)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Installed bundle to %s\n", dir)
+	fmt.Printf("  %d cache files added, %d already existed\n", result.CacheFiles, result.SkippedFiles)
+	fmt.Printf("You can now launch it with %s\n", gchalk.Bold("minepkg launch"))
+	return nil
+}
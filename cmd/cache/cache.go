@@ -0,0 +1,10 @@
+package cache
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var SubCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage and share the local package cache",
+}
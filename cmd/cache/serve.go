@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/lancache"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	runner := &serveRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:   "serve",
+		Short: "Shares the local package, library and asset cache over HTTP",
+		Long: `
+Starts an HTTP server that shares the local package, library and asset cache
+with other machines in your network.
+
+Other machines can fetch from this cache first by setting the "lanCachePeer" config option:
+  minepkg config set lanCachePeer 192.168.1.5
+`,
+		Args: cobra.ExactArgs(0),
+	}, runner)
+
+	cmd.Flags().StringVar(&runner.addr, "addr", fmt.Sprintf(":%d", lancache.DefaultPort), "Address to listen on")
+	cmd.Flags().BoolVarP(&runner.verbose, "verbose", "v", false, "Log every served file")
+
+	SubCmd.AddCommand(cmd.Command)
+}
+
+type serveRunner struct {
+	addr    string
+	verbose bool
+}
+
+func (s *serveRunner) RunE(cmd *cobra.Command, args []string) error {
+	userCache, err := os.UserCacheDir()
+	if err != nil {
+		return err
+	}
+
+	server := &lancache.Server{CacheDir: filepath.Join(userCache, "minepkg")}
+	if s.verbose {
+		server.OnServe = func(r *http.Request, file string) {
+			fmt.Printf("%s %s\n", gchalk.Gray(r.RemoteAddr), r.URL.Path)
+		}
+	}
+
+	fmt.Printf("Sharing %s on %s\n", server.CacheDir, s.addr)
+	for _, ip := range localIPs() {
+		fmt.Printf("  minepkg config set lanCachePeer %s\n", ip)
+	}
+
+	return server.ListenAndServe(s.addr)
+}
+
+// localIPs returns the non-loopback IPv4 addresses of this machine
+func localIPs() []string {
+	ips := []string{}
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return ips
+	}
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.To4() == nil || ipNet.IP.IsLoopback() {
+			continue
+		}
+		ips = append(ips, ipNet.IP.String())
+	}
+	return ips
+}
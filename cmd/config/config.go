@@ -21,6 +21,14 @@ var config = map[string]configEntry{
 	"verboselogging":      {configKindBool, ""},
 	"acceptminecrafteula": {configKindBool, ""},
 	"init.defaultsource":  {configKindBool, ""},
+	"mirrorpreset":        {configKindString, "Use a predefined (eg. \"bmclapi\") or user defined ([mirrorPresets.<name>]) set of download mirrors"},
+	"ipfsgateway":         {configKindString, "IPFS gateway to fetch packages from first (eg. \"https://ipfs.io\")"},
+	"ipfsapi":             {configKindString, "API of a local IPFS node to fetch packages from first (eg. \"http://127.0.0.1:5001\")"},
+	"lancachepeer":        {configKindString, "Address of a machine running \"minepkg cache serve\" to download from first"},
+	"backupkeep":          {configKindInt, "Number of world backups to keep per world (default 10)"},
+	"backupmaxage":        {configKindString, "Remove world backups older than this (eg. \"720h\")"},
+	"backuponlaunch":      {configKindBool, "Back up the server world before every \"minepkg launch --server\""},
+	"backupinterval":      {configKindString, "Back up the server world in this interval while it is running (eg. \"6h\")"},
 }
 
 var SubCmd = &cobra.Command{
@@ -0,0 +1,56 @@
+package export
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/curseforge"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	runner := &curseforgeRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:   "curseforge",
+		Short: "Exports this modpack as a CurseForge modpack zip",
+		Long: `
+Writes a zip file with a CurseForge manifest.json. The overwrites are placed under "overrides/".
+CurseForge manifests can only reference files hosted on CurseForge. Locked mods with a
+CurseForge download url (".../mods/<project id>/files/<file id>/download") are referenced,
+all other mods are included in "overrides/mods".
+`,
+		Aliases: []string{"cf"},
+		Args:    cobra.ExactArgs(0),
+	}, runner)
+
+	cmd.Flags().StringVarP(&runner.output, "output", "o", "", "Output file (default is <package-name>.curseforge.zip)")
+
+	SubCmd.AddCommand(cmd.Command)
+}
+
+type curseforgeRunner struct {
+	output string
+}
+
+func (c *curseforgeRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := prepareInstance()
+	if err != nil {
+		return err
+	}
+
+	output := c.output
+	if output == "" {
+		output = instance.Manifest.Package.Name + ".curseforge.zip"
+	}
+	err = writeExport(output, func(w io.Writer) error {
+		return curseforge.Export(w, instance)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Done. %s can be imported with the CurseForge app\n", gchalk.Bold(output))
+	return nil
+}
@@ -0,0 +1,72 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/minepkg/minepkg/internals/globals"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/minepkg/minepkg/internals/launcher"
+	"github.com/minepkg/minepkg/pkg/manifest"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var logger = globals.Logger
+
+var SubCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Exports this modpack for other launchers and platforms",
+}
+
+// prepareInstance returns the instance in the current directory with all dependencies downloaded
+func prepareInstance() (*instances.Instance, error) {
+	instance, err := instances.NewFromWd()
+	if err != nil {
+		return nil, err
+	}
+	instance.MinepkgAPI = globals.ApiClient
+
+	cliLauncher := launcher.Launcher{
+		Instance:       instance,
+		NonInteractive: viper.GetBool("nonInteractive"),
+		UseSystemJava:  true,
+	}
+	if err := cliLauncher.Prepare(); err != nil {
+		return nil, err
+	}
+
+	// data packs are exported with the worlds they are linked to
+	for _, dep := range instance.Lockfile.Dependencies {
+		if dep.Type != manifest.DependencyLockTypeDatapack {
+			continue
+		}
+		paths, err := instance.InstallPaths(dep)
+		if err != nil {
+			return nil, err
+		}
+		if len(paths) == 0 {
+			logger.Warn(fmt.Sprintf("%s is not exported, because this modpack has no world to put the data pack in", dep.Name))
+		}
+	}
+	return instance, nil
+}
+
+// writeExport creates output and writes the export to it using write.
+// The file is removed again if writing fails
+func writeExport(output string, write func(w io.Writer) error) error {
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fmt.Println("\nWriting export to " + output)
+	if err := write(f); err != nil {
+		f.Close()
+		os.Remove(output)
+		return err
+	}
+	return nil
+}
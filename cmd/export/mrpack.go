@@ -0,0 +1,56 @@
+package export
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/mrpack"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	runner := &mrpackRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:   "mrpack",
+		Short: "Exports this modpack as a Modrinth modpack (.mrpack)",
+		Long: `
+Writes a .mrpack file. Locked mods are referenced by their download url and hashes,
+the overwrites are placed under "overrides/".
+Modrinth only accepts downloads from cdn.modrinth.com, github.com, raw.githubusercontent.com
+and gitlab.com. Mods hosted anywhere else (like minepkg.io) are placed under "overrides/mods".
+`,
+		Aliases: []string{"modrinth"},
+		Args:    cobra.ExactArgs(0),
+	}, runner)
+
+	cmd.Flags().StringVarP(&runner.output, "output", "o", "", "Output file (default is <package-name>.mrpack)")
+
+	SubCmd.AddCommand(cmd.Command)
+}
+
+type mrpackRunner struct {
+	output string
+}
+
+func (m *mrpackRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := prepareInstance()
+	if err != nil {
+		return err
+	}
+
+	output := m.output
+	if output == "" {
+		output = instance.Manifest.Package.Name + ".mrpack"
+	}
+	err = writeExport(output, func(w io.Writer) error {
+		return mrpack.Export(w, instance)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Done. %s is ready to be uploaded to Modrinth\n", gchalk.Bold(output))
+	return nil
+}
@@ -0,0 +1,54 @@
+package export
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/multimc"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	runner := &multimcRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:   "multimc",
+		Short: "Exports this modpack as a MultiMC / Prism Launcher instance zip",
+		Long: `
+Writes a zip file that can be imported with MultiMC or Prism Launcher ("Add Instance" → "Import from zip").
+It contains the instance.cfg, mmc-pack.json, all locked mods and the overwrites.
+`,
+		Aliases: []string{"prism", "mmc"},
+		Args:    cobra.ExactArgs(0),
+	}, runner)
+
+	cmd.Flags().StringVarP(&runner.output, "output", "o", "", "Output file (default is <package-name>.multimc.zip)")
+
+	SubCmd.AddCommand(cmd.Command)
+}
+
+type multimcRunner struct {
+	output string
+}
+
+func (m *multimcRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := prepareInstance()
+	if err != nil {
+		return err
+	}
+
+	output := m.output
+	if output == "" {
+		output = instance.Manifest.Package.Name + ".multimc.zip"
+	}
+	err = writeExport(output, func(w io.Writer) error {
+		return multimc.Export(w, instance)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Done. Import %s in MultiMC or Prism Launcher\n", gchalk.Bold(output))
+	return nil
+}
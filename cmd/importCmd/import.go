@@ -0,0 +1,10 @@
+package importCmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var SubCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Converts instances of other launchers to minepkg modpacks",
+}
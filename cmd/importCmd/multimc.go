@@ -0,0 +1,98 @@
+package importCmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/globals"
+	"github.com/minepkg/minepkg/internals/multimc"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	runner := &multimcRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:   "multimc <instance-dir> [directory]",
+		Short: "Imports a MultiMC or Prism Launcher instance",
+		Long: `
+Creates a minepkg.toml from a MultiMC or Prism Launcher instance in the given directory
+(defaults to the current directory).
+
+Fabric mod jars are matched against the releases on minepkg.io by the id and version in
+their fabric.mod.json (the file has to be identical to the published one). Jars without
+a match and the config directory are copied to the overwrites.
+`,
+		Example: `  minepkg import multimc ~/.local/share/PrismLauncher/instances/my-pack`,
+		Aliases: []string{"prism", "mmc"},
+		Args:    cobra.RangeArgs(1, 2),
+	}, runner)
+
+	cmd.Flags().BoolVarP(&runner.force, "force", "f", false, "Overwrite an existing minepkg.toml")
+
+	SubCmd.AddCommand(cmd.Command)
+}
+
+type multimcRunner struct {
+	force bool
+}
+
+func (m *multimcRunner) RunE(cmd *cobra.Command, args []string) error {
+	target := "."
+	if len(args) == 2 {
+		target = args[1]
+	}
+	target, err := filepath.Abs(target)
+	if err != nil {
+		return err
+	}
+
+	manifestPath := filepath.Join(target, "minepkg.toml")
+	if _, err := os.Stat(manifestPath); err == nil && !m.force {
+		return &commands.CliError{
+			Text: "this directory already contains a minepkg.toml",
+			Suggestions: []string{
+				"Import the instance into an empty directory",
+				fmt.Sprintf("Overwrite it with %s", gchalk.Bold("--force")),
+			},
+		}
+	}
+
+	instance, err := multimc.Open(args[0])
+	if err != nil {
+		if errors.Is(err, multimc.ErrNoInstance) {
+			return &commands.CliError{
+				Text: fmt.Sprintf("%s is not a MultiMC or Prism Launcher instance", args[0]),
+				Suggestions: []string{
+					"Pass the instance directory that contains the instance.cfg",
+				},
+			}
+		}
+		return err
+	}
+
+	fmt.Printf("Importing %s (%s)\n", gchalk.Bold(instance.Config.Name()), instance.Platform())
+	result, err := instance.Import(context.TODO(), globals.ApiClient.FindJarRelease, target)
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(manifestPath, result.Manifest.Buffer().Bytes(), 0644); err != nil {
+		return err
+	}
+
+	fmt.Printf("  %d mods found on minepkg.io\n", len(result.Matched))
+	if len(result.Unmatched) != 0 {
+		fmt.Printf("  %d mods were not found and are copied to the overwrites:\n", len(result.Unmatched))
+		for _, jar := range result.Unmatched {
+			fmt.Println("    " + gchalk.Gray(jar))
+		}
+	}
+	fmt.Printf("Created %s. Launch it with %s\n", manifestPath, gchalk.Bold("minepkg launch"))
+	return nil
+}
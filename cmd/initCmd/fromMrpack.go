@@ -0,0 +1,64 @@
+package initCmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/downloadmgr"
+	"github.com/minepkg/minepkg/internals/mrpack"
+	"github.com/minepkg/minepkg/internals/pack"
+)
+
+// initFromMrpack creates a modpack from the Modrinth modpack in `fromMrpack`
+func (i *initRunner) initFromMrpack() error {
+	mp, err := mrpack.Open(i.fromMrpack)
+	if err != nil {
+		return err
+	}
+	defer mp.Close()
+
+	result, err := mp.Manifest()
+	if err != nil {
+		return err
+	}
+	man := result.Manifest
+	man.Package.Author = getDefaultAuthor()
+
+	fmt.Printf("Importing %s (%d mods)\n", gchalk.Bold(mp.Index.Name), len(man.Dependencies))
+
+	extracted, err := mp.ExtractOverrides("overwrites")
+	if err != nil {
+		return err
+	}
+	if extracted != 0 {
+		logger.Info(fmt.Sprintf(" ✓ Extracted %d files to overwrites", extracted))
+	}
+
+	// files outside of the mods folder can not be dependencies, they are downloaded into the overwrites
+	if len(result.Files) != 0 {
+		mgr := downloadmgr.New()
+		for _, file := range result.Files {
+			if err := pack.SanitizeExtractPath(file.Path, "overwrites"); err != nil {
+				return err
+			}
+			item := downloadmgr.NewHTTPItem(file.Downloads[0], filepath.Join("overwrites", filepath.FromSlash(file.Path)))
+			item.FallbackURLs = append(item.FallbackURLs, file.Downloads[1:]...)
+			item.Sha1 = file.Hashes["sha1"]
+			mgr.Add(item)
+		}
+		if err := mgr.Start(context.TODO()); err != nil {
+			return err
+		}
+		logger.Info(fmt.Sprintf(" ✓ Downloaded %d files to overwrites", len(result.Files)))
+	}
+
+	for _, file := range result.ServerOnly {
+		logger.Warn("Skipped server only file " + file.Path)
+	}
+
+	writeManifest(man)
+	logger.Info(" ✓ Created minepkg.toml")
+	return nil
+}
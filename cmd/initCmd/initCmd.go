@@ -32,13 +32,15 @@ func New() *cobra.Command {
 
 	cmd.Flags().BoolVarP(&runner.force, "force", "f", false, "Overwrite the minepkg.toml if one exists")
 	cmd.Flags().BoolVarP(&runner.yes, "yes", "y", false, "Choose defaults for all questions. (same as --non-interactive)")
+	cmd.Flags().StringVar(&runner.fromMrpack, "from-mrpack", "", "Create a modpack from a Modrinth modpack (.mrpack file)")
 
 	return cmd.Command
 }
 
 type initRunner struct {
-	force bool
-	yes   bool
+	force      bool
+	yes        bool
+	fromMrpack string
 }
 
 func (i *initRunner) RunE(cmd *cobra.Command, args []string) error {
@@ -46,6 +48,10 @@ func (i *initRunner) RunE(cmd *cobra.Command, args []string) error {
 		logger.Fail("This directory already contains a minepkg.toml. Use --force to overwrite it")
 	}
 
+	if i.fromMrpack != "" {
+		return i.initFromMrpack()
+	}
+
 	man := defaultManifest()
 
 	if i.yes || viper.GetBool("nonInteractive") {
@@ -61,13 +67,16 @@ func (i *initRunner) RunE(cmd *cobra.Command, args []string) error {
 	}
 
 	logger.Info("[package]")
+	types := []string{"modpack", "mod", "resourcepack", "shaderpack", "datapack"}
 	cursorPos := 0
-	if man.Package.Type == "mod" {
-		cursorPos = 1
+	for n, t := range types {
+		if man.Package.Type == t {
+			cursorPos = n
+		}
 	}
 	man.Package.Type = utils.SelectPrompt(&promptui.Select{
 		Label:     "Type",
-		Items:     []string{"modpack", "mod"},
+		Items:     types,
 		CursorPos: cursorPos,
 		// Default: man.Package.Type,
 	})
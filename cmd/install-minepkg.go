@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -16,6 +15,7 @@ import (
 	"github.com/minepkg/minepkg/internals/commands"
 	"github.com/minepkg/minepkg/internals/downloadmgr"
 	"github.com/minepkg/minepkg/internals/globals"
+	"github.com/minepkg/minepkg/internals/workspace"
 )
 
 func (i *installRunner) installFromMinepkg(mods []string) error {
@@ -25,7 +25,8 @@ func (i *installRunner) installFromMinepkg(mods []string) error {
 	task := logger.NewTask(3)
 	task.Step("📚", "Finding packages")
 
-	releases := make([]*api.Release, len(mods))
+	releases := make([]*api.Release, 0, len(mods))
+	members := make([]*workspace.Member, 0)
 
 	s := spinner.New(spinner.CharSets[9], 300*time.Millisecond) // Build our new spinner
 	s.Prefix = " "
@@ -42,7 +43,7 @@ func (i *installRunner) installFromMinepkg(mods []string) error {
 		instance.SaveLockfile()
 	}
 
-	for i, name := range mods {
+	for _, name := range mods {
 		comp := strings.Split(name, "@")
 		name = comp[0]
 		version := "latest"
@@ -50,6 +51,12 @@ func (i *installRunner) installFromMinepkg(mods []string) error {
 			version = comp[1]
 		}
 
+		// workspace members are used from source
+		if ws := instance.Workspace(); ws != nil && ws.Member(name) != nil {
+			members = append(members, ws.Member(name))
+			continue
+		}
+
 		reqs := &api.RequirementQuery{
 			Version:   version,
 			Minecraft: instance.Lockfile.MinecraftVersion(),
@@ -79,15 +86,18 @@ func (i *installRunner) installFromMinepkg(mods []string) error {
 			logger.Info("Could not find package " + name + "@" + version)
 			os.Exit(1)
 		}
-		releases[i] = release
+		releases = append(releases, release)
 	}
 
-	if len(releases) == 1 {
+	switch {
+	case len(releases) == 1 && len(members) == 0:
 		logger.Info("Installing " + releases[0].Package.Name + "@" + releases[0].Package.Version)
-	} else {
+	case len(releases) == 0 && len(members) == 1:
+		logger.Info("Installing " + members[0].Name() + " (workspace)")
+	default:
 		// TODO: list mods
 		prompt := promptui.Prompt{
-			Label:     fmt.Sprintf("Install %d mods", len(releases)),
+			Label:     fmt.Sprintf("Install %d mods", len(releases)+len(members)),
 			IsConfirm: true,
 			Default:   "Y",
 		}
@@ -108,6 +118,18 @@ func (i *installRunner) installFromMinepkg(mods []string) error {
 			instance.Manifest.AddDevDependency(release.Package.Name, "^"+release.Package.Version)
 		}
 	}
+	for _, member := range members {
+		version := "*"
+		if member.Manifest.Package.Version != "" {
+			version = "^" + member.Manifest.Package.Version
+		}
+		if !i.dev {
+			instance.Manifest.AddDependency(member.Name(), version)
+		} else {
+			fmt.Println("Adding as dev dependency!")
+			instance.Manifest.AddDevDependency(member.Name(), version)
+		}
+	}
 
 	instance.UpdateLockfileDependencies(context.TODO())
 	for _, dep := range instance.Lockfile.Dependencies {
@@ -120,9 +142,7 @@ func (i *installRunner) installFromMinepkg(mods []string) error {
 
 	task.Step("🚚", fmt.Sprintf("Downloading %d Packages", len(missingFiles)))
 	for _, m := range missingFiles {
-		p := filepath.Join(instance.PackageCacheDir(), m.Name, m.Version+m.FileExt())
-		item := downloadmgr.HTTPItem{URL: m.URL, Target: p, Sha256: m.Sha256}
-		mgr.Add(&item)
+		mgr.Add(instance.DependencyDownloader(m))
 	}
 
 	s.Start()
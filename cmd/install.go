@@ -1,12 +1,15 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/minepkg/minepkg/internals/commands"
 	"github.com/minepkg/minepkg/internals/globals"
 	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/minepkg/minepkg/internals/workspace"
 	"github.com/spf13/cobra"
 )
 
@@ -21,17 +24,30 @@ func init() {
 
 	cmd.Flags().BoolVarP(&runner.dev, "dev", "D", false, "Install as a dev dependency only.")
 	cmd.Flags().BoolVar(&runner.dev, "save-dev", false, "Same as --dev (for you node devs)")
+	cmd.Flags().BoolVar(&runner.adopt, "adopt", false, "Add fabric mods that were placed in the mods folder by hand to the minepkg.toml (if they are published)")
 
 	rootCmd.AddCommand(cmd.Command)
 }
 
 type installRunner struct {
-	dev bool
+	dev   bool
+	adopt bool
 
 	instance *instances.Instance
 }
 
 func (i *installRunner) RunE(cmd *cobra.Command, args []string) error {
+	// no args in a workspace root: installing the dependencies of all members
+	if len(args) == 0 {
+		ws, err := workspaceRoot()
+		if err != nil {
+			return err
+		}
+		if ws != nil {
+			return installWorkspace(ws)
+		}
+	}
+
 	instance, err := instances.NewFromWd()
 	if err != nil {
 		return err
@@ -40,6 +56,10 @@ func (i *installRunner) RunE(cmd *cobra.Command, args []string) error {
 	i.instance = instance
 	fmt.Printf("Installing to %s\n\n", instance.Desc())
 
+	if i.adopt {
+		return i.adoptMods()
+	}
+
 	// no args: installing minepkg.toml dependencies
 	if len(args) == 0 {
 		return installManifest(instance)
@@ -59,3 +79,69 @@ func (i *installRunner) RunE(cmd *cobra.Command, args []string) error {
 	// fallback to minepkg
 	return i.installFromMinepkg(args)
 }
+
+// adoptMods adds the published mods the player placed in the mods folder to the manifest
+func (i *installRunner) adoptMods() error {
+	instance := i.instance
+	adopted, err := instance.AdoptMods(context.TODO(), globals.ApiClient.FindJarRelease)
+	if err != nil {
+		return err
+	}
+	if len(adopted) == 0 {
+		fmt.Println("No unmanaged mods could be found on minepkg")
+		return nil
+	}
+	for file, release := range adopted {
+		fmt.Printf(" - %s → %s@%s\n", file, release.Package.Name, release.Package.Version)
+	}
+	if err := instance.SaveManifest(); err != nil {
+		return err
+	}
+	if err := installManifest(instance); err != nil {
+		return err
+	}
+
+	// the jars are only removed after their releases were installed
+	if err := instance.RemoveAdoptedMods(adopted); err != nil {
+		return err
+	}
+	// releases with the same file name as a removed jar were not linked yet
+	return instance.LinkDependencies()
+}
+
+// workspaceRoot returns the workspace if the working directory is the root of a workspace
+// that is not a package itself. nil is returned otherwise
+func workspaceRoot() (*workspace.Workspace, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	ws, err := workspace.Find(wd)
+	if err != nil || ws == nil {
+		return nil, err
+	}
+	if ws.Manifest.Package.Name != "" || ws.MemberAt(wd) != nil {
+		return nil, nil
+	}
+	return ws, nil
+}
+
+// installWorkspace installs the minepkg.toml dependencies of all workspace members
+func installWorkspace(ws *workspace.Workspace) error {
+	members, err := ws.Sorted()
+	if err != nil {
+		return err
+	}
+	for _, member := range members {
+		instance, err := instances.NewFromDir(member.Dir)
+		if err != nil {
+			return err
+		}
+		instance.MinepkgAPI = globals.ApiClient
+		fmt.Printf("Installing to %s\n\n", instance.Desc())
+		if err := installManifest(instance); err != nil {
+			return fmt.Errorf("installing %s failed: %w", member.Name(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,302 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jwalton/gchalk"
+	"github.com/manifoldco/promptui"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var instancesCmd = &cobra.Command{
+	Use:     "instances",
+	Short:   "Manage all instances on this machine (eg. created by \"minepkg join\")",
+	Aliases: []string{"instance"},
+}
+
+func init() {
+	instancesCmd.AddCommand(commands.New(&cobra.Command{
+		Use:     "list",
+		Short:   "Lists all known instances",
+		Aliases: []string{"ls"},
+		Args:    cobra.ExactArgs(0),
+	}, &instancesListRunner{}).Command)
+
+	instancesCmd.AddCommand(commands.New(&cobra.Command{
+		Use:   "show <name>",
+		Short: "Shows details of an instance",
+		Args:  cobra.ExactArgs(1),
+	}, &instancesShowRunner{}).Command)
+
+	instancesCmd.AddCommand(commands.New(&cobra.Command{
+		Use:   "path <name>",
+		Short: "Prints the directory of an instance",
+		Args:  cobra.ExactArgs(1),
+	}, &instancesPathRunner{}).Command)
+
+	launch := &instancesLaunchRunner{}
+	launchCmd := commands.New(&cobra.Command{
+		Use:     "launch <name>",
+		Short:   "Launches an instance by name",
+		Aliases: []string{"run", "start", "play"},
+		Args:    cobra.ExactArgs(1),
+	}, launch)
+	launch.addFlags(launchCmd.Command)
+	instancesCmd.AddCommand(launchCmd.Command)
+
+	rm := &instancesRmRunner{}
+	rmCmd := commands.New(&cobra.Command{
+		Use:     "rm <name>",
+		Short:   "Deletes an instance",
+		Long:    "Deletes instances in the global instances directory. Other instances are only removed from the list.",
+		Aliases: []string{"remove", "delete"},
+		Args:    cobra.ExactArgs(1),
+	}, rm)
+	rmCmd.Flags().BoolVarP(&rm.yes, "yes", "y", false, "Do not ask for confirmation")
+	instancesCmd.AddCommand(rmCmd.Command)
+
+	instancesCmd.AddCommand(commands.New(&cobra.Command{
+		Use:   "rename <name> <new-name>",
+		Short: "Renames an instance",
+		Args:  cobra.ExactArgs(2),
+	}, &instancesRenameRunner{}).Command)
+
+	rootCmd.AddCommand(instancesCmd)
+}
+
+// openRegistry opens the instance registry and registers unknown instances
+// from the global instances directory
+func openRegistry() (*instances.Registry, *instances.Instance, error) {
+	global := instances.New()
+	registry, err := instances.OpenRegistry(global.GlobalDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := registry.Discover(global.InstancesDir()); err != nil {
+		return nil, nil, err
+	}
+	return registry, global, nil
+}
+
+func errUnknownInstance(name string) error {
+	return &commands.CliError{
+		Text: fmt.Sprintf("instance %s does not exist", name),
+		Suggestions: []string{
+			fmt.Sprintf("List all instances with %s", gchalk.Bold("minepkg instances list")),
+		},
+	}
+}
+
+func findInstance(name string) (*instances.Registry, *instances.RegistryEntry, *instances.Instance, error) {
+	registry, global, err := openRegistry()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	entry := registry.Get(name)
+	if entry == nil {
+		return nil, nil, nil, errUnknownInstance(name)
+	}
+	return registry, entry, global, nil
+}
+
+// instanceDetails returns the pack name and Minecraft version of the instance (if known)
+func instanceDetails(entry *instances.RegistryEntry) (string, string) {
+	instance, err := instances.NewFromDir(entry.Directory)
+	if err != nil {
+		return "?", "?"
+	}
+	pack := instance.Manifest.Package.BasedOn
+	if pack == "" {
+		pack = instance.Manifest.Package.Name
+	}
+	mcVersion := instance.Manifest.Requirements.Minecraft
+	if instance.Lockfile != nil && instance.Lockfile.HasRequirements() {
+		mcVersion = instance.Lockfile.MinecraftVersion()
+	}
+	return pack, mcVersion
+}
+
+func humanBytes(size int64) string {
+	switch {
+	case size >= 1024*1024*1024:
+		return fmt.Sprintf("%.1f GiB", float64(size)/(1024*1024*1024))
+	case size >= 1024*1024:
+		return fmt.Sprintf("%.1f MiB", float64(size)/(1024*1024))
+	case size >= 1024:
+		return fmt.Sprintf("%.1f KiB", float64(size)/1024)
+	}
+	return fmt.Sprintf("%d B", size)
+}
+
+func humanLastPlayed(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+type instancesListRunner struct{}
+
+func (i *instancesListRunner) RunE(cmd *cobra.Command, args []string) error {
+	registry, _, err := openRegistry()
+	if err != nil {
+		return err
+	}
+	if err := registry.Save(); err != nil {
+		return err
+	}
+
+	list := registry.List()
+	if len(list) == 0 {
+		fmt.Println("No instances yet. Join a server with \"minepkg join\" or launch a modpack to create one.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tPACK\tMINECRAFT\tSIZE\tLAST PLAYED")
+	for _, entry := range list {
+		pack, mcVersion := instanceDetails(entry)
+		size, _ := entry.DiskUsage()
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", entry.Name, pack, mcVersion, humanBytes(size), humanLastPlayed(entry.LastPlayed))
+	}
+	return w.Flush()
+}
+
+type instancesShowRunner struct{}
+
+func (i *instancesShowRunner) RunE(cmd *cobra.Command, args []string) error {
+	_, entry, _, err := findInstance(args[0])
+	if err != nil {
+		return err
+	}
+
+	pack, mcVersion := instanceDetails(entry)
+	size, _ := entry.DiskUsage()
+	fmt.Println(gchalk.Bold(entry.Name))
+	fmt.Printf("  Pack:        %s\n", pack)
+	fmt.Printf("  Minecraft:   %s\n", mcVersion)
+	fmt.Printf("  Directory:   %s\n", entry.Directory)
+	fmt.Printf("  Disk usage:  %s\n", humanBytes(size))
+	fmt.Printf("  Last played: %s\n", humanLastPlayed(entry.LastPlayed))
+	return nil
+}
+
+type instancesPathRunner struct{}
+
+func (i *instancesPathRunner) RunE(cmd *cobra.Command, args []string) error {
+	_, entry, _, err := findInstance(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Println(entry.Directory)
+	return nil
+}
+
+type instancesLaunchRunner struct {
+	launchRunner
+}
+
+func (i *instancesLaunchRunner) RunE(cmd *cobra.Command, args []string) error {
+	_, entry, _, err := findInstance(args[0])
+	if err != nil {
+		return err
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if err := os.Chdir(entry.Directory); err != nil {
+		return err
+	}
+	defer os.Chdir(wd)
+
+	return i.launchRunner.RunE(cmd, []string{})
+}
+
+type instancesRmRunner struct {
+	yes bool
+}
+
+func (i *instancesRmRunner) RunE(cmd *cobra.Command, args []string) error {
+	registry, entry, global, err := findInstance(args[0])
+	if err != nil {
+		return err
+	}
+
+	managed := entry.IsManaged(global.InstancesDir())
+	if managed && !i.yes {
+		if viper.GetBool("nonInteractive") {
+			return fmt.Errorf("refusing to delete %s without confirmation. Use --yes", entry.Directory)
+		}
+		prompt := promptui.Prompt{
+			Label:     fmt.Sprintf("Delete %s", entry.Directory),
+			IsConfirm: true,
+		}
+		if _, err := prompt.Run(); err != nil {
+			fmt.Println("Aborting")
+			return nil
+		}
+	}
+
+	if managed {
+		if err := os.RemoveAll(entry.Directory); err != nil {
+			return err
+		}
+	}
+	registry.Remove(entry.Name)
+	if err := registry.Save(); err != nil {
+		return err
+	}
+
+	if managed {
+		fmt.Printf("Deleted %s\n", entry.Name)
+	} else {
+		fmt.Printf("Removed %s from the list. %s was not deleted\n", entry.Name, entry.Directory)
+	}
+	return nil
+}
+
+type instancesRenameRunner struct{}
+
+func (i *instancesRenameRunner) RunE(cmd *cobra.Command, args []string) error {
+	registry, entry, global, err := findInstance(args[0])
+	if err != nil {
+		return err
+	}
+	newName := args[1]
+	if registry.Get(newName) != nil {
+		return fmt.Errorf("instance %s already exists", newName)
+	}
+	if filepath.Base(newName) != newName {
+		return fmt.Errorf("invalid instance name %s", newName)
+	}
+
+	// managed instances are also moved to a directory with the new name
+	if entry.IsManaged(global.InstancesDir()) {
+		newDir := filepath.Join(global.InstancesDir(), newName)
+		if _, err := os.Stat(newDir); err == nil {
+			return fmt.Errorf("%s already exists", newDir)
+		}
+		if err := os.Rename(entry.Directory, newDir); err != nil {
+			return err
+		}
+		entry.Directory = newDir
+	}
+
+	if err := registry.Rename(entry.Name, newName); err != nil {
+		return err
+	}
+	if err := registry.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("Renamed %s to %s\n", args[0], newName)
+	return nil
+}
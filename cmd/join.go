@@ -77,6 +77,7 @@ func (j *joinRunner) RunE(cmd *cobra.Command, args []string) error {
 	os.MkdirAll(instanceDir, os.ModePerm)
 
 	instance.Directory = instanceDir
+	instance.ServerAddress = ip + ":" + port
 	wd, err := os.Getwd()
 	if err != nil {
 		return err
@@ -106,6 +107,10 @@ func (j *joinRunner) RunE(cmd *cobra.Command, args []string) error {
 	if err := cliLauncher.Prepare(); err != nil {
 		return err
 	}
+	// saved so the instance can be managed with "minepkg instances"
+	if err := instance.SaveManifest(); err != nil {
+		return err
+	}
 
 	opts := &instances.LaunchOptions{
 		JoinServer: ip + ":" + port,
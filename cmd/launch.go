@@ -8,10 +8,13 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/cmd/backup"
 	"github.com/minepkg/minepkg/internals/api"
 	"github.com/minepkg/minepkg/internals/commands"
 	"github.com/minepkg/minepkg/internals/globals"
@@ -34,19 +37,25 @@ Alternatively: Can be used in directories containing a minepkg.toml manifest to
 		Args:    cobra.MaximumNArgs(1),
 	}, runner)
 
-	cmd.Flags().BoolVarP(&runner.serverMode, "server", "s", false, "Start a server instead of a client")
-	cmd.Flags().BoolVarP(&runner.forceUpdate, "update", "u", false, "Force check for updates before starting")
-	cmd.Flags().BoolVar(&runner.debugMode, "debug", false, "Do not start, just debug")
-	cmd.Flags().BoolVar(&runner.offlineMode, "offline", false, "Start the server in offline mode (server only)")
-	cmd.Flags().BoolVar(&runner.onlyPrepare, "only-prepare", false, "Only prepare, skip launching")
-	cmd.Flags().BoolVar(&runner.crashTest, "crashtest", false, "Stop server after it's online (can be used for testing)")
-	cmd.Flags().BoolVar(&runner.noBuild, "no-build", false, "Skip build (if any)")
-	cmd.Flags().BoolVar(&runner.demo, "demo", false, "Start Minecraft in demo mode (without auth)")
-	runner.overwrites = launcher.CmdOverwriteFlags(cmd.Command)
+	runner.addFlags(cmd.Command)
 
 	rootCmd.AddCommand(cmd.Command)
 }
 
+// addFlags adds the launch flags to the given command
+func (l *launchRunner) addFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&l.serverMode, "server", "s", false, "Start a server instead of a client")
+	cmd.Flags().BoolVarP(&l.forceUpdate, "update", "u", false, "Force check for updates before starting")
+	cmd.Flags().BoolVar(&l.debugMode, "debug", false, "Do not start, just debug")
+	cmd.Flags().BoolVar(&l.offlineMode, "offline", false, "Start the server in offline mode (server only)")
+	cmd.Flags().BoolVar(&l.onlyPrepare, "only-prepare", false, "Only prepare, skip launching")
+	cmd.Flags().BoolVar(&l.crashTest, "crashtest", false, "Stop server after it's online (can be used for testing)")
+	cmd.Flags().BoolVar(&l.noBuild, "no-build", false, "Skip build (if any)")
+	cmd.Flags().BoolVar(&l.demo, "demo", false, "Start Minecraft in demo mode (without auth)")
+	cmd.Flags().StringVar(&l.profileName, "profile", "", "Use a launch profile from the minepkg.toml")
+	l.overwrites = launcher.CmdOverwriteFlags(cmd)
+}
+
 type launchRunner struct {
 	serverMode  bool
 	debugMode   bool
@@ -56,8 +65,10 @@ type launchRunner struct {
 	noBuild     bool
 	demo        bool
 	forceUpdate bool
+	profileName string
 
 	overwrites *launcher.OverwriteFlags
+	profile    *manifest.LaunchProfile
 
 	instance *instances.Instance
 }
@@ -86,6 +97,10 @@ func (l *launchRunner) RunE(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if err := l.applyProfile(cmd); err != nil {
+		return err
+	}
+
 	switch {
 	case l.crashTest && !l.serverMode:
 		logger.Fail("Can only crashtest servers. append --server to crashtest")
@@ -115,6 +130,11 @@ func (l *launchRunner) RunE(cmd *cobra.Command, args []string) error {
 
 	cliLauncher.ApplyOverWrites(l.overwrites)
 
+	// build workspace members first, so their jars can be linked
+	if err := l.buildWorkspaceMembers(); err != nil {
+		return err
+	}
+
 	if err := cliLauncher.Prepare(); err != nil {
 		return err
 	}
@@ -146,6 +166,19 @@ func (l *launchRunner) RunE(cmd *cobra.Command, args []string) error {
 		Demo:           l.demo,
 		RamMiB:         l.overwrites.Ram,
 	}
+	if l.profile != nil {
+		opts.JvmArgs = l.profile.JvmArgs
+		opts.StartSave = l.profile.StartSave
+		opts.JoinServer = l.profile.JoinServer
+	}
+
+	if l.serverMode {
+		stopBackups, err := l.serverBackups(opts)
+		if err != nil {
+			return err
+		}
+		defer stopBackups()
+	}
 
 	launchErr := make(chan error)
 	crashErr := make(chan error)
@@ -204,6 +237,115 @@ func (l *launchRunner) RunE(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// applyProfile applies the launch profile set with `--profile`.
+// Flags that are set explicitly take precedence over the profile
+func (l *launchRunner) applyProfile(cmd *cobra.Command) error {
+	if l.profileName == "" {
+		return nil
+	}
+
+	profiles := l.instance.Manifest.Launch.Profiles
+	profile, ok := profiles[l.profileName]
+	if !ok || profile == nil {
+		names := make([]string, 0, len(profiles))
+		for name := range profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		suggestion := "Add a [launch.profiles." + l.profileName + "] section to your minepkg.toml"
+		if len(names) != 0 {
+			suggestion = "Available profiles: " + strings.Join(names, ", ")
+		}
+		return &commands.CliError{
+			Text:        fmt.Sprintf("launch profile %s does not exist", l.profileName),
+			Suggestions: []string{suggestion},
+		}
+	}
+
+	flags := cmd.Flags()
+	if profile.Server && !flags.Changed("server") {
+		l.serverMode = true
+	}
+	if profile.Ram != 0 && !flags.Changed("ram") {
+		l.overwrites.Ram = profile.Ram
+	}
+	if profile.Java != "" && !flags.Changed("java") {
+		l.overwrites.Java = profile.Java
+	}
+	l.profile = profile
+	l.instance.ServerAddress = profile.JoinServer
+	return nil
+}
+
+// serverBackups backs up the server world before launching (if "backupOnLaunch" is set) and
+// starts the scheduled backups (if "backupInterval" is set). The returned func stops the schedule.
+// Scheduled backups need to control the server, so its console is set in opts
+func (l *launchRunner) serverBackups(opts *instances.LaunchOptions) (func(), error) {
+	if viper.GetBool("backupOnLaunch") {
+		created, err := backup.Worlds(l.instance, true)
+		if err != nil {
+			return nil, fmt.Errorf("backup before launch failed: %w", err)
+		}
+		for _, b := range created {
+			fmt.Println(gchalk.Gray("│ backed up world as " + b.Name()))
+		}
+	}
+
+	interval, err := backup.Interval()
+	if err != nil || interval == 0 {
+		return func() {}, err
+	}
+
+	console, err := launcher.NewServerConsole()
+	if err != nil {
+		return nil, err
+	}
+	opts.Stdin = console.Stdin()
+	opts.Stdout = console
+
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := l.backupRunningServer(console); err != nil {
+					logger.Warn("Scheduled backup failed: " + err.Error())
+				}
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+	}, nil
+}
+
+// backupRunningServer backs up the world of a running server. Saving is turned off
+// while the world is zipped, so the backup does not contain partially written files
+func (l *launchRunner) backupRunningServer(console *launcher.ServerConsole) error {
+	if err := console.Command("save-off"); err != nil {
+		return err
+	}
+	defer console.Command("save-on")
+
+	if err := console.SaveAll(time.Minute); err != nil {
+		return err
+	}
+	created, err := backup.Worlds(l.instance, true)
+	if err != nil {
+		return err
+	}
+	for _, b := range created {
+		logger.Info("Backed up world as " + b.Name())
+	}
+	return nil
+}
+
 func crashTest() error {
 	tries := 0
 
@@ -287,6 +429,33 @@ func (l *launchRunner) instanceFromModpack(modpack string) (*instances.Instance,
 	return instance, nil
 }
 
+// buildWorkspaceMembers builds all workspace mods that the instance depends on
+func (l *launchRunner) buildWorkspaceMembers() error {
+	ws := l.instance.Workspace()
+	if ws == nil || l.noBuild {
+		return nil
+	}
+	members, err := ws.Dependencies(l.instance.Manifest)
+	if err != nil {
+		return err
+	}
+
+	for _, member := range members {
+		if member.Manifest.Package.Type != manifest.TypeMod {
+			continue
+		}
+		logger.Info("Building workspace member " + member.Name())
+		memberInstance := &instances.Instance{Directory: member.Dir, Manifest: member.Manifest}
+		build := memberInstance.BuildMod()
+		cmdTerminalOutput(build)
+		build.Start()
+		if err := build.Wait(); err != nil {
+			return fmt.Errorf("build step of %s failed: %w", member.Name(), err)
+		}
+	}
+	return nil
+}
+
 func (l *launchRunner) buildMod() error {
 	if !l.noBuild {
 		build := l.instance.BuildMod()
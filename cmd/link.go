@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/minepkg/minepkg/pkg/manifest"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	link := commands.New(&cobra.Command{
+		Use:   "link [mod]",
+		Short: "Uses a locally built mod instead of the published release",
+		Long: `Run "minepkg link" in a mod directory to register its build output.
+Then run "minepkg link <mod>" in a modpack to use the locally built jar instead of the locked release.
+The minepkg.toml of the modpack is not changed.`,
+		Args: cobra.MaximumNArgs(1),
+	}, &linkRunner{})
+
+	unlink := commands.New(&cobra.Command{
+		Use:   "unlink [mod]",
+		Short: "Removes a link created with \"minepkg link\"",
+		Long: `Run "minepkg unlink" in a mod directory to remove its registration.
+Run "minepkg unlink <mod>" in a modpack to use the locked release again.`,
+		Args: cobra.MaximumNArgs(1),
+	}, &unlinkRunner{})
+
+	rootCmd.AddCommand(link.Command)
+	rootCmd.AddCommand(unlink.Command)
+}
+
+type linkRunner struct{}
+
+func (l *linkRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := instances.NewFromWd()
+	if err != nil {
+		return err
+	}
+	links, err := instances.OpenLinks(instance.GlobalDir)
+	if err != nil {
+		return err
+	}
+
+	// no args: registering this mod
+	if len(args) == 0 {
+		if instance.Manifest.Package.Type != manifest.TypeMod {
+			return &commands.CliError{
+				Text: "only mods can be registered for linking",
+				Suggestions: []string{
+					fmt.Sprintf("Run %s in a modpack to use a registered mod", gchalk.Bold("minepkg link <mod>")),
+				},
+			}
+		}
+		name := instance.Manifest.Package.Name
+		links.Mods[name] = instance.Directory
+		if err := links.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("Registered %s (%s)\n", name, instance.Directory)
+		fmt.Printf("Run %s in a modpack to use your local build\n", gchalk.Bold("minepkg link "+name))
+		return nil
+	}
+
+	name := args[0]
+	if _, ok := links.Mods[name]; !ok {
+		return &commands.CliError{
+			Text: fmt.Sprintf("%s is not registered for linking", name),
+			Suggestions: []string{
+				fmt.Sprintf("Run %s in the directory of %s first", gchalk.Bold("minepkg link"), name),
+			},
+		}
+	}
+	if instance.Lockfile == nil || instance.Lockfile.Dependencies[name] == nil {
+		return &commands.CliError{
+			Text: fmt.Sprintf("%s is not a dependency of this package", name),
+			Suggestions: []string{
+				fmt.Sprintf("Install it first with %s", gchalk.Bold("minepkg install "+name)),
+			},
+		}
+	}
+
+	linked, err := instance.LinkedMods()
+	if err != nil {
+		return err
+	}
+	for _, mod := range linked {
+		if mod == name {
+			fmt.Printf("%s is already linked\n", name)
+			return nil
+		}
+	}
+	if err := instance.SetLinkedMods(append(linked, name)); err != nil {
+		return err
+	}
+	fmt.Printf("Linked %s. Your local build is used on the next launch\n", name)
+	return nil
+}
+
+type unlinkRunner struct{}
+
+func (u *unlinkRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := instances.NewFromWd()
+	if err != nil {
+		return err
+	}
+
+	// no args: removing the registration of this mod
+	if len(args) == 0 {
+		links, err := instances.OpenLinks(instance.GlobalDir)
+		if err != nil {
+			return err
+		}
+		delete(links.Mods, instance.Manifest.Package.Name)
+		if err := links.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("Removed the registration of %s\n", instance.Manifest.Package.Name)
+		return nil
+	}
+
+	name := args[0]
+	linked, err := instance.LinkedMods()
+	if err != nil {
+		return err
+	}
+	remaining := make([]string, 0, len(linked))
+	for _, mod := range linked {
+		if mod != name {
+			remaining = append(remaining, mod)
+		}
+	}
+	if len(remaining) == len(linked) {
+		return fmt.Errorf("%s is not linked", name)
+	}
+	if err := instance.SetLinkedMods(remaining); err != nil {
+		return err
+	}
+	fmt.Printf("Unlinked %s. The locked release is used on the next launch\n", name)
+	return nil
+}
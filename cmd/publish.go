@@ -1,13 +1,10 @@
 package cmd
 
 import (
-	"archive/zip"
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"io/ioutil"
-	"log"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -19,7 +16,9 @@ import (
 	"github.com/minepkg/minepkg/internals/commands"
 	"github.com/minepkg/minepkg/internals/globals"
 	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/minepkg/minepkg/internals/pack"
 	"github.com/minepkg/minepkg/internals/utils"
+	"github.com/minepkg/minepkg/internals/workspace"
 	"github.com/minepkg/minepkg/pkg/manifest"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -40,21 +39,107 @@ func init() {
 	cmd.Flags().BoolVarP(&runner.dry, "dry", "", false, "Dry run without publishing")
 	cmd.Flags().BoolVarP(&runner.noBuild, "no-build", "", false, "Skips building the package")
 	cmd.Flags().StringVarP(&runner.versionName, "release", "r", "", "Release version number to publish (overwrites version in manifest)")
+	cmd.Flags().StringVar(&runner.file, "file", "", "Publish this jar or zip file instead of building one (mods and packs)")
+	cmd.Flags().BoolVar(&runner.listFilesOnly, "list-files", false, "Only list the files that would be published (not for mods)")
+	cmd.Flags().BoolVar(&runner.workspace, "workspace", false, "Publish all workspace members with unpublished versions in dependency order")
 
 	rootCmd.AddCommand(cmd.Command)
 }
 
 type publishRunner struct {
-	unofficial  bool
-	dry         bool
-	noBuild     bool
-	versionName string
-	file        string
+	unofficial    bool
+	dry           bool
+	noBuild       bool
+	versionName   string
+	file          string
+	listFilesOnly bool
+	workspace     bool
 
 	release *api.Release
 }
 
 func (p *publishRunner) RunE(cmd *cobra.Command, args []string) error {
+	if p.workspace {
+		return p.publishWorkspace(cmd, args)
+	}
+
+	instance, err := instances.NewFromWd()
+	if err != nil {
+		return err
+	}
+
+	if p.listFilesOnly {
+		return p.listFiles(instance)
+	}
+	return p.publish(cmd, args, instance)
+}
+
+// publishWorkspace publishes every workspace member whose version is not published yet.
+// Members are published after the members they depend on
+func (p *publishRunner) publishWorkspace(cmd *cobra.Command, args []string) error {
+	if p.versionName != "" {
+		return &commands.CliError{
+			Text: "--release can not be used with --workspace",
+			Suggestions: []string{
+				"Set the version in the minepkg.toml of each member instead",
+			},
+		}
+	}
+	if p.file != "" {
+		return &commands.CliError{
+			Text: "--file can not be used with --workspace",
+			Suggestions: []string{
+				"Publish the member with the prebuilt file on its own",
+			},
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	ws, err := workspace.Find(wd)
+	if err != nil {
+		return err
+	}
+	if ws == nil {
+		return &commands.CliError{
+			Text: "this directory is not part of a workspace",
+			Suggestions: []string{
+				"Add a [workspace] section with members to the minepkg.toml at the root of your repository",
+			},
+		}
+	}
+
+	members, err := ws.Sorted()
+	if err != nil {
+		return err
+	}
+	for _, member := range members {
+		m := member.Manifest
+		release, err := globals.ApiClient.GetRelease(context.TODO(), m.PlatformString(), m.Package.Name+"@"+m.Package.Version)
+		switch {
+		case err == nil && release.Meta.Published:
+			logger.Info(fmt.Sprintf("Skipping %s@%s (already published)", m.Package.Name, m.Package.Version))
+			continue
+		case err != nil && err != api.ErrNotFound:
+			return fmt.Errorf("checking the release of %s failed: %w", m.Package.Name, err)
+		}
+
+		logger.Headline(fmt.Sprintf("Publishing %s@%s", m.Package.Name, m.Package.Version))
+		instance, err := instances.NewFromDir(member.Dir)
+		if err != nil {
+			return err
+		}
+		p.release = nil
+		if err := p.publish(cmd, args, instance); err != nil {
+			return fmt.Errorf("publishing %s failed: %w", m.Package.Name, err)
+		}
+	}
+	return nil
+}
+
+func (p *publishRunner) publish(cmd *cobra.Command, args []string, instance *instances.Instance) error {
 	apiClient := globals.ApiClient
 	nonInteractive := viper.GetBool("nonInteractive")
 
@@ -62,17 +147,14 @@ func (p *publishRunner) RunE(cmd *cobra.Command, args []string) error {
 	tasks.Step("📚", "Preparing Publish")
 
 	tasks.Log("Checking minepkg.toml")
-	instance, err := instances.NewFromWd()
-	if err != nil {
-		return err
-	}
-
 	m := instance.Manifest
 
+	// resource, shader and data packs do not need a mod loader
+	isPack := m.Package.Type == manifest.TypeResourcepack || m.Package.Type == manifest.TypeShaderpack || m.Package.Type == manifest.TypeDatapack
 	switch {
 	case m.Requirements.Minecraft == "":
 		logger.Fail("Your minepkg.toml is missing a minecraft version under [requirements]")
-	case m.Requirements.ForgeLoader == "" && m.Requirements.FabricLoader == "":
+	case !isPack && m.Requirements.ForgeLoader == "" && m.Requirements.FabricLoader == "":
 		logger.Fail("Your minepkg.toml is missing either forge or fabric in [requirements]")
 	}
 
@@ -104,7 +186,7 @@ func (p *publishRunner) RunE(cmd *cobra.Command, args []string) error {
 				os.Exit(0)
 			}
 		}
-		readme, _ := getReadme()
+		readme, _ := getReadme(instance.Directory)
 		project, err = apiClient.CreateProject(&api.Project{
 			Name:        m.Package.Name,
 			Type:        m.Package.Type,
@@ -176,12 +258,11 @@ func (p *publishRunner) RunE(cmd *cobra.Command, args []string) error {
 
 	tasks.Step("🏗", "Building")
 
+	// decided per package, the runner is reused for every workspace member
 	buildCmd := m.Dev.BuildCommand
-	if (buildCmd == "" && m.Package.Type == manifest.TypeModpack) || p.file != "" {
-		p.noBuild = true
-	}
+	noBuild := p.noBuild || (buildCmd == "" && m.Package.Type != manifest.TypeMod) || p.file != ""
 
-	if !p.noBuild {
+	if !noBuild {
 		build := instance.BuildMod()
 		cmdTerminalOutput(build)
 		build.Start()
@@ -197,18 +278,28 @@ func (p *publishRunner) RunE(cmd *cobra.Command, args []string) error {
 
 	var artifact string
 
-	if m.Package.Type == manifest.TypeMod {
+	switch m.Package.Type {
+	case manifest.TypeMod:
 		// find se jar
 		tasks.Log("Finding jar file")
 		artifact, err = p.findJar(instance)
 		if err != nil {
 			return err
 		}
-	} else {
+	case manifest.TypeModpack:
 		// find all modpack related files
 		tasks.Log("Archiving modpack file")
-		// TODO: can fail, better logging, allow modpacks without any files
-		artifact = buildModpackZIP()
+		artifact, err = buildModpackZIP(instance)
+		if err != nil {
+			return err
+		}
+	default:
+		// resource, shader & data packs
+		tasks.Log("Archiving pack file")
+		artifact, err = p.findPack(instance)
+		if err != nil {
+			return err
+		}
 	}
 
 	tasks.Step("☁", "Uploading package")
@@ -218,7 +309,7 @@ func (p *publishRunner) RunE(cmd *cobra.Command, args []string) error {
 		if artifact != "" {
 			logger.Info("Build package can be found here: " + artifact)
 		}
-		os.Exit(0)
+		return nil
 	}
 
 	if p.release == nil {
@@ -270,8 +361,8 @@ func (p *publishRunner) uploadArtifact(artifact string) error {
 	return nil
 }
 
-func getReadme() (string, error) {
-	files, err := ioutil.ReadDir(".")
+func getReadme(dir string) (string, error) {
+	files, err := ioutil.ReadDir(dir)
 	// something is wrong
 	if err != nil {
 		return "", err
@@ -280,7 +371,7 @@ func getReadme() (string, error) {
 	readme := ""
 	for _, file := range files {
 		if strings.HasPrefix(strings.ToLower(file.Name()), "readme") {
-			readme = filepath.Join("./", file.Name())
+			readme = filepath.Join(dir, file.Name())
 		}
 	}
 
@@ -296,109 +387,93 @@ func getReadme() (string, error) {
 
 }
 
-func buildModpackZIP() string {
-	tmpZip, err := ioutil.TempFile("", "modpack-*.zip")
+// buildModpackZIP archives the package files of the modpack. It returns an empty string if there are no files
+func buildModpackZIP(instance *instances.Instance) (string, error) {
+	files, err := instance.PackageFiles()
 	if err != nil {
-		panic(err)
-	}
-	archive := zip.NewWriter(tmpZip)
-	fileCount := 0
-
-	// TODO: custom ignore list
-	c, err := addToZip(archive, "./overwrites", defaultFilter)
-	if err != nil {
-		log.Println(err)
-	}
-	fileCount += c
-
-	c, err = addToZip(archive, "./overwrites/saves", savesFilter)
-	if err != nil {
-		log.Println(err)
+		return "", err
 	}
-	fileCount += c
-
-	if fileCount == 0 {
-		os.Remove(tmpZip.Name())
-		return ""
+	if len(files) == 0 {
+		return "", nil
 	}
-
-	archive.Close()
-
-	return tmpZip.Name()
+	return buildZIP(instance.OverwritesDir(), files, "modpack-*.zip")
 }
 
-type filter func(string) bool
-
-func addToZip(archive *zip.Writer, path string, filter ...filter) (int, error) {
-	addCount := 0
-	filepath.Walk(path, func(origPath string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		// get paths as if "overwites" is the root
-		path, err = filepath.Rel("overwrites", origPath)
-		if err != nil {
-			return err
-		}
-
-		source, err := os.Open(origPath)
+// findPack returns the --file or archives the pack files of a resource, shader or data pack
+func (p *publishRunner) findPack(instance *instances.Instance) (string, error) {
+	if p.file != "" {
+		abs, err := filepath.Abs(p.file)
 		if err != nil {
-			return err
-		}
-		defer source.Close()
-
-		if info.IsDir() {
-			return nil
+			return "", fmt.Errorf("provided --file path '%s' is invalid:\n  %w", p.file, err)
 		}
+		return abs, nil
+	}
 
-		// skip filtered paths
-		for _, f := range filter {
-			if !f(path) {
-				return nil
+	m := instance.Manifest
+	if m.Package.Type != manifest.TypeShaderpack {
+		if _, err := os.Stat(filepath.Join(instance.Directory, "pack.mcmeta")); err != nil {
+			return "", &commands.CliError{
+				Text: fmt.Sprintf("%s has no pack.mcmeta", m.Package.Type),
+				Suggestions: []string{
+					"Add a pack.mcmeta next to your minepkg.toml",
+					"Or publish an already packed zip file with --file",
+				},
 			}
 		}
+	}
 
-		target, err := archive.Create(path)
-		if err != nil {
-			return err
-		}
-		_, err = io.Copy(target, source)
-		if err != nil {
-			return err
-		}
-		addCount++
-		return nil
-	})
-	return addCount, nil
+	files, err := instance.PackFiles()
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("%s contains no files", m.Package.Type)
+	}
+	return buildZIP(instance.Directory, files, m.Package.Type+"-*.zip")
 }
 
-func savesFilter(path string) bool {
-	no := []string{"advancements", "playerdata", "stats", "session.lock"}
-	parts := strings.Split(path, string(filepath.Separator))
-
-	if len(parts) > 1 {
-		for _, disallowed := range no {
-			// check the third part for filtered paths (eg. find stats in `saves/save-name/stats`)
-			if strings.HasPrefix(parts[2], disallowed) {
-				return false
-			}
-		}
+// buildZIP archives the files (relative to dir) into a temporary zip file
+func buildZIP(dir string, files []string, pattern string) (string, error) {
+	tmpZip, err := ioutil.TempFile("", pattern)
+	if err != nil {
+		return "", err
 	}
+	defer tmpZip.Close()
 
-	return true
+	if err := pack.WriteZip(tmpZip, dir, files); err != nil {
+		os.Remove(tmpZip.Name())
+		return "", err
+	}
+	return tmpZip.Name(), nil
 }
 
-func defaultFilter(path string) bool {
-	no := []string{"minecraft", ".git", "minepkg.toml", "minepkg-lock.toml", ".", "saves"}
-
-	for _, disallowed := range no {
-		if strings.HasPrefix(path, disallowed) {
-			return false
+// listFiles prints the files that would be included in the modpack or pack
+func (p *publishRunner) listFiles(instance *instances.Instance) error {
+	var files []string
+	var err error
+	switch instance.Manifest.Package.Type {
+	case manifest.TypeMod:
+		return &commands.CliError{
+			Text: "--list-files does not work for mods",
+			Suggestions: []string{
+				"Mods are published as the jar file built by your build command",
+			},
 		}
+	case manifest.TypeModpack:
+		files, err = instance.PackageFiles()
+	default:
+		files, err = instance.PackFiles()
 	}
-
-	return true
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		fmt.Println(filepath.ToSlash(file))
+	}
+	if len(files) == 0 {
+		logger.Info("No files would be published. Only the minepkg.toml is used")
+	}
+	return nil
 }
 
 func (p *publishRunner) findJar(instance *instances.Instance) (string, error) {
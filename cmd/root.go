@@ -7,13 +7,23 @@ import (
 	"strings"
 
 	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/cmd/backup"
 	"github.com/minepkg/minepkg/cmd/bump"
+	"github.com/minepkg/minepkg/cmd/bundle"
+	"github.com/minepkg/minepkg/cmd/cache"
 	"github.com/minepkg/minepkg/cmd/config"
 	"github.com/minepkg/minepkg/cmd/dev"
+	"github.com/minepkg/minepkg/cmd/export"
+	"github.com/minepkg/minepkg/cmd/importCmd"
 	"github.com/minepkg/minepkg/cmd/initCmd"
+	"github.com/minepkg/minepkg/cmd/saves"
 	"github.com/minepkg/minepkg/internals/commands"
 	"github.com/minepkg/minepkg/internals/credentials"
 	"github.com/minepkg/minepkg/internals/globals"
+	"github.com/minepkg/minepkg/internals/ipfs"
+	"github.com/minepkg/minepkg/internals/lancache"
+	"github.com/minepkg/minepkg/internals/mirrors"
+	"github.com/spf13/cast"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -23,6 +33,7 @@ var logger = globals.Logger
 
 var (
 	cfgFile   string
+	workDir   string
 	globalDir = "/tmp"
 
 	// Version is the current version. it should be set by goreleaser
@@ -92,7 +103,7 @@ func initRoot() {
 		fmt.Println("Using MINEPKG_API_KEY for authentication")
 	}
 
-	cobra.OnInitialize(initConfig)
+	cobra.OnInitialize(initWorkDir, initConfig)
 
 	configDir, err := os.UserConfigDir()
 	if err != nil {
@@ -103,6 +114,7 @@ func initRoot() {
 
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", fmt.Sprintf("config file (default is %s/config.toml)", configPath))
+	rootCmd.PersistentFlags().StringVarP(&workDir, "directory", "C", "", "Run as if minepkg was started in this directory")
 	rootCmd.PersistentFlags().BoolP("accept-minecraft-eula", "a", false, "Accept Minecraft's eula. See https://www.minecraft.net/en-us/eula/")
 	// rootCmd.PersistentFlags().BoolP("system-java", "", false, "Use system java instead of internal installation for launching Minecraft server or client")
 	rootCmd.PersistentFlags().BoolP("verbose", "", false, "More verbose logging. Not really implemented yet")
@@ -118,10 +130,26 @@ func initRoot() {
 	// subcommands
 	rootCmd.AddCommand(dev.SubCmd)
 	rootCmd.AddCommand(config.SubCmd)
+	rootCmd.AddCommand(cache.SubCmd)
+	rootCmd.AddCommand(bundle.SubCmd)
+	rootCmd.AddCommand(backup.SubCmd)
+	rootCmd.AddCommand(saves.SubCmd)
+	rootCmd.AddCommand(importCmd.SubCmd)
+	rootCmd.AddCommand(export.SubCmd)
 	rootCmd.AddCommand(initCmd.New())
 	rootCmd.AddCommand(bump.New())
 }
 
+// initWorkDir changes the working directory if "--directory" is set
+func initWorkDir() {
+	if workDir == "" {
+		return
+	}
+	if err := os.Chdir(workDir); err != nil {
+		logger.Fail("Can not use --directory: " + err.Error())
+	}
+}
+
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
 	if viper.GetBool("noColor") || os.Getenv("CI") != "" {
@@ -157,4 +185,66 @@ func initConfig() {
 		logger.Warn("NOT using default minepkg API URL: " + viper.GetString("apiUrl"))
 		globals.ApiClient.APIUrl = viper.GetString("apiUrl")
 	}
+
+	initMirrors()
+	initLANCache()
+
+	ipfs.Default.Gateway = viper.GetString("ipfsGateway")
+	ipfs.Default.API = viper.GetString("ipfsAPI")
+}
+
+// initLANCache configures the LAN cache peer from the "lanCachePeer" config option.
+// The peer is not contacted until something is downloaded
+func initLANCache() {
+	peerURL := viper.GetString("lanCachePeer")
+	if peerURL == "" {
+		return
+	}
+	userCache, err := os.UserCacheDir()
+	if err != nil {
+		logger.Warn("Can not use the LAN cache peer: " + err.Error())
+		return
+	}
+
+	peer := lancache.NewPeer(peerURL, filepath.Join(userCache, "minepkg"))
+	peer.OnUnreachable = func(err error) {
+		logger.Warn("LAN cache peer " + peer.URL + " is not reachable. Downloading from the internet")
+	}
+	if viper.GetBool("verboseLogging") {
+		fmt.Println("Using LAN cache peer: " + peer.URL)
+	}
+	lancache.DefaultPeer = peer
+}
+
+// initMirrors configures the download mirrors from the "mirrorPreset", "mirrorPresets" and "mirrors" config options
+func initMirrors() {
+	// hosts contain dots, so the maps are read as a whole instead of per key
+	for name, hosts := range viper.GetStringMap("mirrorPresets") {
+		mirrors.Presets[strings.ToLower(name)] = configMirrors(cast.ToStringMap(hosts))
+	}
+
+	if preset := viper.GetString("mirrorPreset"); preset != "" {
+		presetMirrors, err := mirrors.Preset(preset)
+		if err != nil {
+			logger.Fail(err.Error())
+		}
+		mirrors.Default.Merge(presetMirrors)
+	}
+
+	// custom mirrors are tried before the preset ones
+	mirrors.Default.Merge(configMirrors(viper.GetStringMap("mirrors")))
+
+	if len(mirrors.Default) != 0 {
+		// the API falls back to the original URL if a mirror is not reachable
+		globals.ApiClient.HTTP = mirrors.Default.Client()
+	}
+}
+
+// configMirrors converts a host to mirror URLs map from the config
+func configMirrors(hosts map[string]interface{}) mirrors.Mirrors {
+	m := mirrors.Mirrors{}
+	for host, bases := range hosts {
+		m[strings.ToLower(host)] = cast.ToStringSlice(bases)
+	}
+	return m
 }
@@ -0,0 +1,74 @@
+package saves
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/backup"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	runner := &exportRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:   "export <world>",
+		Short: "Exports a savegame as a zip file",
+		Args:  cobra.ExactArgs(1),
+	}, runner)
+
+	cmd.Flags().StringVarP(&runner.output, "output", "o", "", "Output file (default is <world>.zip)")
+
+	SubCmd.AddCommand(cmd.Command)
+}
+
+type exportRunner struct {
+	output string
+}
+
+func (e *exportRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := instances.NewFromWd()
+	if err != nil {
+		return err
+	}
+
+	worldDir, err := findWorld(instance, args[0])
+	if err != nil {
+		return err
+	}
+
+	output := e.output
+	if output == "" {
+		output = filepath.Base(worldDir) + ".zip"
+	}
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := backup.WriteWorld(f, worldDir); err != nil {
+		f.Close()
+		os.Remove(output)
+		return err
+	}
+	logger.Info(" ✓ Exported to " + output)
+	return nil
+}
+
+// findWorld returns the directory of the given world
+func findWorld(instance *instances.Instance, name string) (string, error) {
+	worldDir := filepath.Join(instance.SavesDir(), name)
+	if info, err := os.Stat(worldDir); err != nil || !info.IsDir() || name != filepath.Base(name) {
+		return "", &commands.CliError{
+			Text: fmt.Sprintf("savegame %s does not exist", name),
+			Suggestions: []string{
+				fmt.Sprintf("List all savegames with %s", gchalk.Bold("minepkg saves list")),
+			},
+		}
+	}
+	return worldDir, nil
+}
@@ -0,0 +1,55 @@
+package saves
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jwalton/gchalk"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/minepkg/minepkg/internals/saves"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	runner := &importRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:   "import <file.zip>",
+		Short: "Imports a savegame from a zip file",
+		Long: `
+Imports a savegame from a zip file. The zip can contain the world directory
+or the world files (level.dat, region/ …) directly. Existing savegames are never overwritten.
+`,
+		Args: cobra.ExactArgs(1),
+	}, runner)
+
+	cmd.Flags().StringVar(&runner.name, "name", "", "Directory name of the imported world")
+
+	SubCmd.AddCommand(cmd.Command)
+}
+
+type importRunner struct {
+	name string
+}
+
+func (i *importRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := instances.NewFromWd()
+	if err != nil {
+		return err
+	}
+
+	name, err := saves.Import(args[0], instance.SavesDir(), i.name)
+	if err != nil {
+		if errors.Is(err, saves.ErrWorldExists) {
+			return &commands.CliError{
+				Text: "a savegame with the same name already exists",
+				Suggestions: []string{
+					fmt.Sprintf("Choose a different name with %s", gchalk.Bold("--name")),
+				},
+			}
+		}
+		return err
+	}
+	logger.Info(" ✓ Imported savegame " + name)
+	return nil
+}
@@ -0,0 +1,57 @@
+package saves
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/minepkg/minepkg/internals/saves"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	SubCmd.AddCommand(commands.New(&cobra.Command{
+		Use:     "list",
+		Short:   "Lists all savegames of this instance",
+		Aliases: []string{"ls"},
+		Args:    cobra.ExactArgs(0),
+	}, &listRunner{}).Command)
+}
+
+type listRunner struct{}
+
+func (l *listRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := instances.NewFromWd()
+	if err != nil {
+		return err
+	}
+
+	worlds, err := saves.List(instance.SavesDir())
+	if err != nil {
+		return err
+	}
+	if len(worlds) == 0 {
+		fmt.Println("No savegames yet")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DIRECTORY\tNAME\tGAME MODE\tVERSION\tLAST PLAYED")
+	for _, world := range worlds {
+		gameMode := world.GameMode
+		if world.Hardcore {
+			gameMode += " (hardcore)"
+		}
+		version := world.Version
+		if version == "" {
+			version = "?"
+		}
+		fmt.Fprintf(
+			w, "%s\t%s\t%s\t%s\t%s\n",
+			world.Name(), world.LevelName, gameMode, version, world.LastPlayed.Format("2006-01-02 15:04"),
+		)
+	}
+	return w.Flush()
+}
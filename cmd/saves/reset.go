@@ -0,0 +1,125 @@
+package saves
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/manifoldco/promptui"
+	"github.com/minepkg/minepkg/internals/backup"
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/minepkg/minepkg/internals/saves"
+	"github.com/minepkg/minepkg/pkg/manifest"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	runner := &resetRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:   "reset <world>",
+		Short: "Restores the original savegame shipped with this modpack",
+		Long: `
+Replaces a savegame with the original version shipped with this modpack
+(the "saves" directory of this instance or a modpack dependency).
+The current savegame is backed up first (see "minepkg backup list").
+`,
+		Args: cobra.ExactArgs(1),
+	}, runner)
+
+	cmd.Flags().BoolVarP(&runner.yes, "yes", "y", false, "Do not ask for confirmation")
+
+	SubCmd.AddCommand(cmd.Command)
+}
+
+type resetRunner struct {
+	yes bool
+}
+
+func (r *resetRunner) RunE(cmd *cobra.Command, args []string) error {
+	instance, err := instances.NewFromWd()
+	if err != nil {
+		return err
+	}
+	name := args[0]
+	if name != filepath.Base(name) {
+		return fmt.Errorf("invalid savegame name %s", name)
+	}
+
+	reset, err := pristineWorld(instance, name)
+	if err != nil {
+		return err
+	}
+	if reset == nil {
+		return &commands.CliError{
+			Text: fmt.Sprintf("this modpack does not ship a savegame named %s", name),
+			Suggestions: []string{
+				"Savegames are shipped in the \"saves\" directory of a modpack",
+			},
+		}
+	}
+
+	if !r.yes && !viper.GetBool("nonInteractive") {
+		prompt := promptui.Prompt{
+			Label:     fmt.Sprintf("Reset %s to its original state", name),
+			IsConfirm: true,
+		}
+		if _, err := prompt.Run(); err != nil {
+			fmt.Println("Aborting")
+			return nil
+		}
+	}
+
+	worldDir := filepath.Join(instance.SavesDir(), name)
+	if _, err := os.Stat(worldDir); err == nil {
+		b, err := backup.Create(worldDir, instance.BackupsDir())
+		if err != nil {
+			return fmt.Errorf("could not back up the current savegame: %w", err)
+		}
+		logger.Info(" ✓ Backed up the current savegame as " + b.Name())
+	}
+
+	if err := reset(worldDir); err != nil {
+		return err
+	}
+	logger.Info(" ✓ Reset " + name)
+	return nil
+}
+
+// pristineWorld returns a func that writes the original version of the world to a directory.
+// nil is returned if the instance does not ship this world
+func pristineWorld(instance *instances.Instance, name string) (func(worldDir string) error, error) {
+	// local saves (see `Instance.CopyLocalSaves`)
+	local := filepath.Join(instance.Directory, "saves", name)
+	if info, err := os.Stat(local); err == nil && info.IsDir() {
+		return func(worldDir string) error {
+			return saves.ResetFromDir(local, worldDir)
+		}, nil
+	}
+
+	// saves shipped by modpack dependencies
+	if instance.Lockfile == nil {
+		return nil, nil
+	}
+	prefix := "saves/" + name + "/"
+	for _, dep := range instance.Lockfile.Dependencies {
+		if dep.Type != manifest.DependencyLockTypeModpack {
+			continue
+		}
+		zipPath := filepath.Join(instance.PackageCacheDir(), dep.Name, dep.Version+dep.FileExt())
+		found, err := saves.HasZipWorld(zipPath, prefix)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		if found {
+			return func(worldDir string) error {
+				return saves.ResetFromZip(zipPath, prefix, worldDir)
+			}, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,14 @@
+package saves
+
+import (
+	"github.com/minepkg/minepkg/internals/globals"
+	"github.com/spf13/cobra"
+)
+
+var logger = globals.Logger
+
+var SubCmd = &cobra.Command{
+	Use:     "saves",
+	Short:   "Manage the savegames of this instance",
+	Aliases: []string{"worlds"},
+}
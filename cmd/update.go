@@ -102,12 +102,16 @@ Edit the minepkg.toml to change the version requirements.
 
 		for _, asset := range missingAssets {
 			target := filepath.Join(instance.GlobalDir, "assets/objects", asset.UnixPath())
-			mgr.Add(downloadmgr.NewHTTPItem(asset.DownloadURL(), target))
+			item := downloadmgr.NewHTTPItem(asset.DownloadURL(), target)
+			item.Sha1 = asset.Hash
+			mgr.Add(item)
 		}
 
 		for _, lib := range missingLibs {
 			target := filepath.Join(instance.GlobalDir, "libraries", lib.Filepath())
-			mgr.Add(downloadmgr.NewHTTPItem(lib.DownloadURL(), target))
+			item := downloadmgr.NewHTTPItem(lib.DownloadURL(), target)
+			item.Sha1 = lib.Sha1()
+			mgr.Add(item)
 		}
 
 		if err = mgr.Start(context.TODO()); err != nil {
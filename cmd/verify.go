@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/minepkg/minepkg/internals/commands"
+	"github.com/minepkg/minepkg/internals/downloadmgr"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	runner := &verifyRunner{}
+	cmd := commands.New(&cobra.Command{
+		Use:   "verify",
+		Short: "Checks downloaded files for corruption and downloads them again",
+		Long: `
+Rehashes downloaded files and compares them with their expected checksums.
+Corrupt files get downloaded again.
+`,
+		Example: `  minepkg verify --minecraft`,
+		Args:    cobra.ExactArgs(0),
+	}, runner)
+
+	cmd.Flags().BoolVar(&runner.minecraft, "minecraft", false, "Verify the shared Minecraft assets, libraries and jars")
+
+	rootCmd.AddCommand(cmd.Command)
+}
+
+type verifyRunner struct {
+	minecraft bool
+}
+
+func (v *verifyRunner) RunE(cmd *cobra.Command, args []string) error {
+	if !v.minecraft {
+		return &commands.CliError{
+			Text: "nothing to verify",
+			Suggestions: []string{
+				"Use --minecraft to verify the Minecraft assets, libraries and jars",
+			},
+		}
+	}
+
+	instance := instances.New()
+
+	logger.Info("Verifying Minecraft assets, libraries and jars …")
+	corrupt, err := instance.FindCorruptMinecraftFiles()
+	if err != nil {
+		return err
+	}
+
+	if len(corrupt) == 0 {
+		logger.Info("All files are fine")
+		return nil
+	}
+
+	mgr := downloadmgr.New()
+	for _, item := range corrupt {
+		logger.Warn("corrupt: " + item.Target)
+		mgr.Add(item)
+	}
+
+	logger.Info(fmt.Sprintf("Downloading %d corrupt files again", len(corrupt)))
+	if err := mgr.Start(context.TODO()); err != nil {
+		return err
+	}
+	logger.Info("Done")
+
+	return nil
+}
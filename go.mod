@@ -26,6 +26,7 @@ require (
 	github.com/pbnjay/memory v0.0.0-20201129165224-b12e5d931931
 	github.com/pelletier/go-toml v1.9.3
 	github.com/pierrec/lz4/v4 v4.1.8 // indirect
+	github.com/spf13/cast v1.3.1
 	github.com/spf13/cobra v1.2.1
 	github.com/spf13/viper v1.8.1
 	github.com/stoewer/go-strcase v1.2.0
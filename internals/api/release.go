@@ -1,13 +1,18 @@
 package api
 
 import (
+	"archive/zip"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"sort"
 
 	"github.com/Masterminds/semver/v3"
+	"github.com/minepkg/minepkg/internals/fabric"
+	"github.com/minepkg/minepkg/internals/utils"
 	"github.com/minepkg/minepkg/pkg/manifest"
 )
 
@@ -155,3 +160,44 @@ func (m *MinepkgAPI) GetReleaseList(ctx context.Context, project string) ([]*Rel
 	p := Project{client: m, Name: project}
 	return p.GetReleases(ctx, "")
 }
+
+// FindJarRelease gets the fabric release of a mod jar. The release is looked up by the id and version
+// in the fabric.mod.json of the jar and only returned if its sha256 hash matches the jar.
+// Returns `ErrNotFound` if no release matches (this includes all jars that are not fabric mods
+// and jars the API rejects, like dev builds with a "${version}" version)
+func (m *MinepkgAPI) FindJarRelease(ctx context.Context, jar string) (*Release, error) {
+	fabricMan, err := fabric.ReadJarManifest(jar)
+	switch {
+	case errors.Is(err, fabric.ErrNoManifest), errors.Is(err, zip.ErrFormat):
+		return nil, ErrNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	identifier := url.PathEscape(fabricMan.ID) + "@" + url.PathEscape(fabricMan.Version)
+	res, err := m.get(ctx, m.APIUrl+"/releases/fabric/"+identifier)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 400 && res.StatusCode < 500 {
+		return nil, ErrNotFound
+	}
+	if err := checkResponse(res); err != nil {
+		return nil, err
+	}
+	release := &Release{}
+	if err := parseJSON(res, release); err != nil {
+		return nil, err
+	}
+	release.decorate(m)
+
+	hash, err := utils.Sha256File(jar)
+	if err != nil {
+		return nil, err
+	}
+	if release.Meta.Sha256 != hash {
+		return nil, ErrNotFound
+	}
+	return release, nil
+}
@@ -10,6 +10,12 @@ const (
 	TypeMod = "mod"
 	// TypeModpack indicates a modpack
 	TypeModpack = "modpack"
+	// TypeResourcepack indicates a resource pack
+	TypeResourcepack = "resourcepack"
+	// TypeShaderpack indicates a shader pack
+	TypeShaderpack = "shaderpack"
+	// TypeDatapack indicates a data pack
+	TypeDatapack = "datapack"
 )
 
 // User describes a registered user
@@ -0,0 +1,264 @@
+// Package backup creates, restores and rotates world backups
+package backup
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minepkg/minepkg/internals/pack"
+)
+
+// timeFormat is used for the timestamp in backup file names
+const timeFormat = "20060102-150405"
+
+// ErrNotFound is returned if a backup does not exist
+var ErrNotFound = errors.New("backup not found")
+
+// Backup is a single world backup. The zip file contains the world directory
+type Backup struct {
+	// Path is the path to the zip file
+	Path string
+	// World is the name of the backed up world directory
+	World string
+	// Created is the time the backup was created
+	Created time.Time
+	// Size is the size of the zip file in bytes
+	Size int64
+}
+
+// Name returns the backup name (file name without extension)
+func (b *Backup) Name() string {
+	return strings.TrimSuffix(filepath.Base(b.Path), ".zip")
+}
+
+// Retention decides which backups are kept by `Prune`
+type Retention struct {
+	// Keep is the number of backups to keep per world. 0 keeps all
+	Keep int
+	// MaxAge removes older backups. 0 keeps all. The newest backup of each world is never removed
+	MaxAge time.Duration
+}
+
+// parse returns the backup for the given file name or nil if it is not a backup
+func parse(dir string, info os.FileInfo) *Backup {
+	name := strings.TrimSuffix(info.Name(), ".zip")
+	sep := strings.LastIndex(name, "_")
+	if info.IsDir() || name == info.Name() || sep == -1 {
+		return nil
+	}
+	created, err := time.ParseInLocation(timeFormat, name[sep+1:], time.Local)
+	if err != nil {
+		return nil
+	}
+	return &Backup{
+		Path:    filepath.Join(dir, info.Name()),
+		World:   name[:sep],
+		Created: created,
+		Size:    info.Size(),
+	}
+}
+
+// Create writes a backup of worldDir into backupDir
+func Create(worldDir string, backupDir string) (*Backup, error) {
+	if err := os.MkdirAll(backupDir, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	world := filepath.Base(worldDir)
+	now := time.Now()
+	target := filepath.Join(backupDir, world+"_"+now.Format(timeFormat)+".zip")
+	if _, err := os.Stat(target); err == nil {
+		return nil, fmt.Errorf("%s was already backed up a moment ago", world)
+	}
+
+	// written to a temporary file first, so failed backups never show up
+	tmp, err := ioutil.TempFile(backupDir, ".backup-*.zip")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := WriteWorld(tmp, worldDir); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(target)
+	if err != nil {
+		return nil, err
+	}
+	return parse(backupDir, info), nil
+}
+
+// WriteWorld writes worldDir as a zip to w. The zip contains the world directory itself
+func WriteWorld(w io.Writer, worldDir string) error {
+	dir := worldDir
+	prefix := filepath.Base(worldDir)
+	archive := zip.NewWriter(w)
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		// the lock is held by a running game and can not be read on windows
+		if info.IsDir() || info.Name() == "session.lock" {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = path.Join(prefix, filepath.ToSlash(rel))
+		header.Method = zip.Deflate
+		fw, err := archive.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(fw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// List returns all backups in backupDir. The newest come first
+func List(backupDir string) ([]*Backup, error) {
+	entries, err := ioutil.ReadDir(backupDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*Backup{}, nil
+		}
+		return nil, err
+	}
+
+	backups := make([]*Backup, 0, len(entries))
+	for _, entry := range entries {
+		if b := parse(backupDir, entry); b != nil {
+			backups = append(backups, b)
+		}
+	}
+	sort.SliceStable(backups, func(a, b int) bool {
+		return backups[a].Created.After(backups[b].Created)
+	})
+	return backups, nil
+}
+
+// Find returns the backup with the given name. The latest backup of a world
+// is returned if name is a world name
+func Find(backupDir string, name string) (*Backup, error) {
+	backups, err := List(backupDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range backups {
+		if b.Name() == name || b.World == name {
+			return b, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Restore replaces the world in worldsDir with the content of the backup.
+// The current world is only removed after the backup was extracted successfully
+func Restore(b *Backup, worldsDir string) error {
+	if err := os.MkdirAll(worldsDir, os.ModePerm); err != nil {
+		return err
+	}
+	tmpDir, err := ioutil.TempDir(worldsDir, ".restore-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	r, err := zip.OpenReader(b.Path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		if err := pack.SanitizeExtractPath(f.Name, tmpDir); err != nil {
+			return err
+		}
+		if err := pack.ExtractFile(f, filepath.Join(tmpDir, f.Name)); err != nil {
+			return err
+		}
+	}
+
+	worldDir := filepath.Join(worldsDir, b.World)
+	restored := filepath.Join(tmpDir, b.World)
+	if _, err := os.Stat(restored); err != nil {
+		return fmt.Errorf("backup %s does not contain the world %s", b.Name(), b.World)
+	}
+
+	old := filepath.Join(tmpDir, ".old")
+	if err := os.Rename(worldDir, old); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Rename(restored, worldDir); err != nil {
+		// try to put the old world back
+		os.Rename(old, worldDir)
+		return err
+	}
+	return nil
+}
+
+// Prune removes all backups in backupDir that are not kept by the retention policy.
+// It returns the removed backups
+func Prune(backupDir string, retention Retention) ([]*Backup, error) {
+	backups, err := List(backupDir)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := make([]*Backup, 0)
+	perWorld := make(map[string]int)
+	for _, b := range backups {
+		perWorld[b.World]++
+		n := perWorld[b.World]
+		switch {
+		case n == 1:
+			// always keep the latest backup
+			continue
+		case retention.Keep > 0 && n > retention.Keep:
+		case retention.MaxAge > 0 && time.Since(b.Created) > retention.MaxAge:
+		default:
+			continue
+		}
+
+		if err := os.Remove(b.Path); err != nil {
+			return removed, err
+		}
+		removed = append(removed, b)
+	}
+	return removed, nil
+}
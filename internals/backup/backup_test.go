@@ -0,0 +1,79 @@
+package backup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCreateAndRestore(t *testing.T) {
+	savesDir := t.TempDir()
+	backupDir := t.TempDir()
+	worldDir := filepath.Join(savesDir, "My World")
+	os.MkdirAll(filepath.Join(worldDir, "region"), os.ModePerm)
+	ioutil.WriteFile(filepath.Join(worldDir, "level.dat"), []byte("original"), 0644)
+	ioutil.WriteFile(filepath.Join(worldDir, "region", "r.0.0.mca"), []byte("region"), 0644)
+
+	b, err := Create(worldDir, backupDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.World != "My World" {
+		t.Errorf("unexpected world name %s", b.World)
+	}
+
+	// break the world
+	ioutil.WriteFile(filepath.Join(worldDir, "level.dat"), []byte("broken"), 0644)
+	os.RemoveAll(filepath.Join(worldDir, "region"))
+
+	found, err := Find(backupDir, "My World")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Restore(found, savesDir); err != nil {
+		t.Fatal(err)
+	}
+
+	content, _ := ioutil.ReadFile(filepath.Join(worldDir, "level.dat"))
+	if string(content) != "original" {
+		t.Errorf("level.dat was not restored, got %q", content)
+	}
+	if _, err := os.Stat(filepath.Join(worldDir, "region", "r.0.0.mca")); err != nil {
+		t.Error("region file was not restored")
+	}
+
+	entries, _ := ioutil.ReadDir(savesDir)
+	if len(entries) != 1 {
+		t.Errorf("restore left temporary files behind: %v", entries)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	backupDir := t.TempDir()
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		created := now.Add(-time.Duration(i) * 24 * time.Hour)
+		name := "world_" + created.Format(timeFormat) + ".zip"
+		ioutil.WriteFile(filepath.Join(backupDir, name), []byte{}, 0644)
+	}
+	// an old single backup of another world is always kept
+	ioutil.WriteFile(filepath.Join(backupDir, "other_"+now.Add(-100*24*time.Hour).Format(timeFormat)+".zip"), []byte{}, 0644)
+
+	removed, err := Prune(backupDir, Retention{Keep: 4, MaxAge: 60 * time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 2 {
+		t.Fatalf("expected 2 removed backups, got %d", len(removed))
+	}
+
+	left, _ := List(backupDir)
+	if len(left) != 4 {
+		t.Errorf("expected 4 backups to be left, got %d", len(left))
+	}
+	if left[0].World != "world" || !left[0].Created.After(left[1].Created) {
+		t.Error("backups are not sorted newest first")
+	}
+}
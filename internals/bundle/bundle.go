@@ -0,0 +1,340 @@
+// Package bundle creates and installs offline bundles. A bundle is a single zip file
+// containing everything needed to launch an instance without internet access
+package bundle
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/minepkg/minepkg/internals/minecraft"
+	"github.com/minepkg/minepkg/internals/pack"
+	"github.com/minepkg/minepkg/pkg/manifest"
+)
+
+const (
+	// instancePrefix contains the manifest, lockfile & overwrites
+	instancePrefix = "instance/"
+	// cachePrefix contains files that are extracted into the cache directory
+	cachePrefix = "cache/"
+)
+
+// ErrMissingFile is returned when a file that should be bundled was not downloaded yet
+type ErrMissingFile struct {
+	Path string
+}
+
+func (e *ErrMissingFile) Error() string {
+	return fmt.Sprintf("%s is missing. Launch the instance once before bundling it", e.Path)
+}
+
+// Writer writes a bundle zip file. It can also be used to write other zip based formats (eg. exports)
+type Writer struct {
+	zip      *zip.Writer
+	cacheDir string
+	written  map[string]bool
+}
+
+// NewWriter returns a bundle writer that writes to w. cacheDir is the minepkg
+// cache directory (see `instances.Instance.CacheDir`)
+func NewWriter(w io.Writer, cacheDir string) *Writer {
+	return &Writer{
+		zip:      zip.NewWriter(w),
+		cacheDir: cacheDir,
+		written:  make(map[string]bool),
+	}
+}
+
+// AddBytes adds a file with the given content
+func (b *Writer) AddBytes(name string, content []byte) error {
+	if b.written[name] {
+		return nil
+	}
+	b.written[name] = true
+	w, err := b.zip.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(content)
+	return err
+}
+
+// AddFile adds the file at src as name to the bundle. File modes are preserved
+func (b *Writer) AddFile(name string, src string) error {
+	if b.written[name] {
+		return nil
+	}
+
+	info, err := os.Stat(src)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &ErrMissingFile{src}
+		}
+		return err
+	}
+
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	header.Method = zip.Deflate
+
+	w, err := b.zip.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := io.Copy(w, f); err != nil {
+		return err
+	}
+	b.written[name] = true
+	return nil
+}
+
+// AddCacheFile adds a file from the cache directory. The path is relative to the cache directory
+func (b *Writer) AddCacheFile(rel string) error {
+	return b.AddFile(cachePrefix+filepath.ToSlash(rel), filepath.Join(b.cacheDir, rel))
+}
+
+// AddDir adds all files in dir below the given prefix
+func (b *Writer) AddDir(prefix string, dir string) error {
+	return filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		return b.AddFile(path.Join(prefix, filepath.ToSlash(rel)), p)
+	})
+}
+
+// AddZip adds all files of the zip file at src below the given prefix
+func (b *Writer) AddZip(prefix string, src string) error {
+	r, err := zip.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() || f.Name == "minepkg.toml" {
+			continue
+		}
+		name := path.Join(prefix, f.Name)
+		if b.written[name] {
+			continue
+		}
+		b.written[name] = true
+
+		w, err := b.zip.Create(name)
+		if err != nil {
+			return err
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(w, rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddMinecraftFiles adds every file that is copied into the minecraft directory of the instance on launch
+// (overwrites and the content of modpack dependencies) below the given prefix. Overwrites take precedence.
+// The overwrites are read from the minecraft directory, so the instance has to be prepared first
+func (b *Writer) AddMinecraftFiles(prefix string, instance *instances.Instance) error {
+	files, err := instance.OverwriteFiles()
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		name := path.Join(prefix, filepath.ToSlash(file))
+		if err := b.AddFile(name, filepath.Join(instance.McDir(), file)); err != nil {
+			return err
+		}
+	}
+
+	for _, dep := range instance.Lockfile.Dependencies {
+		if dep.Type != manifest.DependencyLockTypeModpack || dep.URL == "" {
+			continue
+		}
+		src := filepath.Join(instance.PackageCacheDir(), dep.Name, dep.Version+dep.FileExt())
+		if _, err := os.Stat(src); err != nil {
+			return &ErrMissingFile{src}
+		}
+		if err := b.AddZip(prefix, src); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close finishes writing the bundle
+func (b *Writer) Close() error {
+	return b.zip.Close()
+}
+
+// Options change what is included in a bundle
+type Options struct {
+	// JavaDir is the directory of a java runtime to include. Java is not included if empty
+	JavaDir string
+	// Server skips the assets, which are only needed by the client
+	Server bool
+}
+
+// Create writes a bundle of the prepared instance to w. It includes the manifest, lockfile, overwrites,
+// every locked package, the launch manifests, libraries, assets and optionally a java runtime
+func Create(w io.Writer, instance *instances.Instance, launchManifest *minecraft.LaunchManifest, opts *Options) error {
+	b := NewWriter(w, instance.CacheDir)
+
+	if err := b.AddBytes(instancePrefix+"minepkg.toml", instance.Manifest.Buffer().Bytes()); err != nil {
+		return err
+	}
+	if err := b.AddBytes(instancePrefix+".minepkg-lock.toml", instance.Lockfile.Buffer().Bytes()); err != nil {
+		return err
+	}
+	if _, err := os.Stat(instance.OverwritesDir()); err == nil {
+		if err := b.AddDir(instancePrefix+"overwrites", instance.OverwritesDir()); err != nil {
+			return err
+		}
+	}
+
+	// locked packages
+	for _, dep := range instance.Lockfile.Dependencies {
+		if dep.URL == "" {
+			continue
+		}
+		rel := filepath.Join("cache", dep.Name, dep.Version+dep.FileExt())
+		if err := b.AddCacheFile(rel); err != nil {
+			return err
+		}
+	}
+
+	// launch manifests & minecraft jar
+	versionDirs := []string{instance.Lockfile.McManifestName(), launchManifest.MinecraftVersion()}
+	for _, versionDir := range versionDirs {
+		dir := filepath.Join(instance.VersionsDir(), versionDir)
+		if _, err := os.Stat(dir); err != nil {
+			continue
+		}
+		if err := b.AddDir(cachePrefix+"versions/"+versionDir, dir); err != nil {
+			return err
+		}
+	}
+
+	for _, lib := range launchManifest.Libraries.Required() {
+		if err := b.AddCacheFile(filepath.Join("libraries", lib.Filepath())); err != nil {
+			return err
+		}
+	}
+
+	if !opts.Server {
+		if err := addAssets(b, launchManifest); err != nil {
+			return err
+		}
+	}
+
+	if opts.JavaDir != "" {
+		if err := b.AddDir(cachePrefix+"java/"+filepath.Base(opts.JavaDir), opts.JavaDir); err != nil {
+			return err
+		}
+	}
+
+	return b.Close()
+}
+
+func addAssets(b *Writer, launchManifest *minecraft.LaunchManifest) error {
+	indexPath := filepath.Join("assets", "indexes", launchManifest.Assets+".json")
+	buf, err := ioutil.ReadFile(filepath.Join(b.cacheDir, indexPath))
+	if err != nil {
+		return &ErrMissingFile{filepath.Join(b.cacheDir, indexPath)}
+	}
+	if err := b.AddCacheFile(indexPath); err != nil {
+		return err
+	}
+
+	assets := minecraft.AssetIndex{}
+	if err := json.Unmarshal(buf, &assets); err != nil {
+		return err
+	}
+	for _, asset := range assets.Objects {
+		if err := b.AddCacheFile(filepath.Join("assets", "objects", asset.UnixPath())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InstallResult contains some stats about an installed bundle
+type InstallResult struct {
+	// CacheFiles is the number of files extracted into the cache directory
+	CacheFiles int
+	// SkippedFiles is the number of cache files that already existed
+	SkippedFiles int
+}
+
+// Install extracts the bundle at bundlePath. Cache files are extracted to cacheDir (existing files are kept),
+// instance files (manifest, lockfile & overwrites) are extracted to instanceDir
+func Install(bundlePath string, cacheDir string, instanceDir string) (*InstallResult, error) {
+	r, err := zip.OpenReader(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	result := &InstallResult{}
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		var target string
+		switch {
+		case strings.HasPrefix(f.Name, cachePrefix):
+			name := strings.TrimPrefix(f.Name, cachePrefix)
+			if err := pack.SanitizeExtractPath(name, cacheDir); err != nil {
+				return nil, err
+			}
+			target = filepath.Join(cacheDir, name)
+			if _, err := os.Stat(target); err == nil {
+				result.SkippedFiles++
+				continue
+			}
+			result.CacheFiles++
+		case strings.HasPrefix(f.Name, instancePrefix):
+			name := strings.TrimPrefix(f.Name, instancePrefix)
+			if err := pack.SanitizeExtractPath(name, instanceDir); err != nil {
+				return nil, err
+			}
+			target = filepath.Join(instanceDir, name)
+		default:
+			continue
+		}
+
+		if err := pack.ExtractFile(f, target); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
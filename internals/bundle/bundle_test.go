@@ -0,0 +1,59 @@
+package bundle
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriter_Install(t *testing.T) {
+	cacheDir := t.TempDir()
+	os.MkdirAll(filepath.Join(cacheDir, "libraries", "org"), os.ModePerm)
+	ioutil.WriteFile(filepath.Join(cacheDir, "libraries", "org", "lib.jar"), []byte("jar"), 0644)
+
+	buf := &bytes.Buffer{}
+	b := NewWriter(buf, cacheDir)
+	if err := b.AddBytes(instancePrefix+"minepkg.toml", []byte("manifest")); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.AddCacheFile(filepath.Join("libraries", "org", "lib.jar")); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.AddCacheFile(filepath.Join("libraries", "missing.jar")); err == nil {
+		t.Fatal("expected missing file error")
+	}
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	bundlePath := filepath.Join(t.TempDir(), "test.bundle.zip")
+	ioutil.WriteFile(bundlePath, buf.Bytes(), 0644)
+
+	targetCache := t.TempDir()
+	instanceDir := t.TempDir()
+	result, err := Install(bundlePath, targetCache, instanceDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.CacheFiles != 1 {
+		t.Errorf("expected 1 cache file, got %d", result.CacheFiles)
+	}
+
+	if content, _ := ioutil.ReadFile(filepath.Join(targetCache, "libraries", "org", "lib.jar")); string(content) != "jar" {
+		t.Errorf("library was not extracted")
+	}
+	if content, _ := ioutil.ReadFile(filepath.Join(instanceDir, "minepkg.toml")); string(content) != "manifest" {
+		t.Errorf("manifest was not extracted")
+	}
+
+	// installing again keeps existing cache files
+	result, err = Install(bundlePath, targetCache, instanceDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.SkippedFiles != 1 {
+		t.Errorf("expected 1 skipped file, got %d", result.SkippedFiles)
+	}
+}
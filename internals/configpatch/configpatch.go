@@ -0,0 +1,222 @@
+// Package configpatch merges patch files into existing config files.
+// This allows overwrites to set a few keys without replacing the whole file.
+//
+// A patch is named after the file it patches plus a patch suffix:
+//
+//	config/foo.json.patch.toml  patches config/foo.json with a TOML document
+//	config/foo.toml.patch.json  patches config/foo.toml with a JSON document
+//	server.properties.patch     patches server.properties with key=value lines
+//
+// Supported targets are .json, .json5, .toml and .properties files. Nested tables are merged
+// recursively, everything else is replaced. A JSON null removes the key (like a JSON merge patch).
+// Only the patched keys are changed, comments and the order of the other keys are kept.
+// JSON files may contain comments and everything else JSON5 allows
+package configpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+)
+
+const (
+	// SuffixToml is the suffix of patches in TOML format
+	SuffixToml = ".patch.toml"
+	// SuffixJSON is the suffix of patches in JSON format
+	SuffixJSON = ".patch.json"
+	// SuffixProperties is the suffix of patches for .properties files
+	SuffixProperties = ".properties.patch"
+)
+
+// ErrUnsupportedTarget is returned if the patched file is not a .json, .json5, .toml or .properties file
+var ErrUnsupportedTarget = errors.New("can only patch .json, .json5, .toml and .properties files")
+
+// Target returns the path of the file that is patched by the patch at path.
+// ok is false if path is not a patch
+func Target(path string) (target string, ok bool) {
+	switch {
+	case strings.HasSuffix(path, SuffixToml):
+		return strings.TrimSuffix(path, SuffixToml), true
+	case strings.HasSuffix(path, SuffixJSON):
+		return strings.TrimSuffix(path, SuffixJSON), true
+	case strings.HasSuffix(path, SuffixProperties):
+		return strings.TrimSuffix(path, ".patch"), true
+	}
+	return "", false
+}
+
+// Apply merges the patch at patchPath into the file at target.
+// The file is created if it does not exist yet
+func Apply(patchPath string, target string) error {
+	patch, err := readPatch(patchPath)
+	if err != nil {
+		return fmt.Errorf("invalid patch %s: %w", filepath.Base(patchPath), err)
+	}
+
+	existing, err := ioutil.ReadFile(target)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	var patched []byte
+	switch filepath.Ext(target) {
+	case ".json", ".json5":
+		patched, err = patchJSON(existing, patch)
+	case ".toml":
+		patched, err = patchToml(existing, patch)
+	case ".properties":
+		patched = patchProperties(existing, patch)
+	default:
+		return fmt.Errorf("%s: %w", filepath.Base(target), ErrUnsupportedTarget)
+	}
+	if err != nil {
+		return fmt.Errorf("could not patch %s: %w", filepath.Base(target), err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(target, patched, 0644)
+}
+
+// readPatch reads the patch file into a (nested) map
+func readPatch(patchPath string) (map[string]interface{}, error) {
+	raw, err := ioutil.ReadFile(patchPath)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case strings.HasSuffix(patchPath, SuffixToml):
+		tree, err := toml.LoadBytes(raw)
+		if err != nil {
+			return nil, err
+		}
+		return tree.ToMap(), nil
+	case strings.HasSuffix(patchPath, SuffixJSON):
+		patch := make(map[string]interface{})
+		if err := json.Unmarshal(raw, &patch); err != nil {
+			return nil, err
+		}
+		return patch, nil
+	case strings.HasSuffix(patchPath, SuffixProperties):
+		patch := make(map[string]interface{})
+		for _, prop := range parseProperties(raw) {
+			patch[prop.key] = prop.value
+		}
+		return patch, nil
+	}
+	return nil, errors.New("unknown patch format")
+}
+
+// merge merges patch into dst. Nested maps are merged, nil values remove the key
+func merge(dst map[string]interface{}, patch map[string]interface{}) {
+	for key, value := range patch {
+		if value == nil {
+			delete(dst, key)
+			continue
+		}
+		patchMap, isMap := value.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if isMap && dstIsMap {
+			merge(dstMap, patchMap)
+			continue
+		}
+		dst[key] = withoutNil(value)
+	}
+}
+
+// patchProperties replaces the values of existing keys in place, so comments and the order
+// are preserved. New keys are appended. Nested patch keys are joined with a dot
+func patchProperties(existing []byte, patch map[string]interface{}) []byte {
+	values := make(map[string]string)
+	flatten("", patch, values)
+
+	lines := strings.Split(string(existing), "\n")
+	if len(existing) == 0 {
+		lines = []string{}
+	} else if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	out := make([]string, 0, len(lines)+len(values))
+	for _, line := range lines {
+		prop, ok := parsePropertyLine(line)
+		if !ok {
+			out = append(out, line)
+			continue
+		}
+		value, patched := values[prop.key]
+		if !patched {
+			out = append(out, line)
+			continue
+		}
+		delete(values, prop.key)
+		if value == "\x00" {
+			// removed key
+			continue
+		}
+		out = append(out, prop.key+"="+value)
+	}
+
+	added := make([]string, 0, len(values))
+	for key, value := range values {
+		if value != "\x00" {
+			added = append(added, key+"="+value)
+		}
+	}
+	sort.Strings(added)
+	out = append(out, added...)
+
+	return []byte(strings.Join(out, "\n") + "\n")
+}
+
+// flatten writes all values of patch into values. nil values are marked with "\x00"
+func flatten(prefix string, patch map[string]interface{}, values map[string]string) {
+	for key, value := range patch {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			flatten(prefix+key+".", v, values)
+		case nil:
+			values[prefix+key] = "\x00"
+		default:
+			values[prefix+key] = fmt.Sprint(v)
+		}
+	}
+}
+
+type property struct {
+	key   string
+	value string
+}
+
+func parseProperties(raw []byte) []property {
+	props := make([]property, 0)
+	for _, line := range strings.Split(string(raw), "\n") {
+		if prop, ok := parsePropertyLine(line); ok {
+			props = append(props, prop)
+		}
+	}
+	return props
+}
+
+// parsePropertyLine parses a `key=value` line. ok is false for comments and empty lines
+func parsePropertyLine(line string) (prop property, ok bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+		return property{}, false
+	}
+	comp := strings.SplitN(line, "=", 2)
+	prop.key = strings.TrimSpace(comp[0])
+	if len(comp) == 2 {
+		prop.value = strings.TrimSpace(comp[1])
+	}
+	return prop, prop.key != ""
+}
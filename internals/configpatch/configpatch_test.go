@@ -0,0 +1,184 @@
+package configpatch
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestTarget(t *testing.T) {
+	tests := map[string]string{
+		"config/foo.json.patch.toml": "config/foo.json",
+		"config/foo.toml.patch.json": "config/foo.toml",
+		"server.properties.patch":    "server.properties",
+	}
+	for patch, expected := range tests {
+		target, ok := Target(patch)
+		if !ok || target != expected {
+			t.Errorf("expected target %s for %s, got %s", expected, patch, target)
+		}
+	}
+	if _, ok := Target("config/foo.json"); ok {
+		t.Error("config/foo.json is not a patch")
+	}
+}
+
+func TestApplyJSON(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "foo.json")
+	patch := filepath.Join(dir, "foo.json.patch.toml")
+	ioutil.WriteFile(target, []byte(`{"keybind": "G", "server": {"pvp": true, "motd": "hi"}}`), 0644)
+	ioutil.WriteFile(patch, []byte("[server]\npvp = false\n"), 0644)
+
+	if err := Apply(patch, target); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, _ := ioutil.ReadFile(target)
+	content := struct {
+		Keybind string
+		Server  struct {
+			Pvp  bool
+			Motd string
+		}
+	}{}
+	if err := json.Unmarshal(raw, &content); err != nil {
+		t.Fatal(err)
+	}
+	if content.Keybind != "G" || content.Server.Pvp || content.Server.Motd != "hi" {
+		t.Fatalf("unexpected patched content: %s", raw)
+	}
+}
+
+func TestApplyProperties(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "server.properties")
+	patch := filepath.Join(dir, "server.properties.patch")
+	ioutil.WriteFile(target, []byte("# comment\nmotd=hi\npvp=true\n"), 0644)
+	ioutil.WriteFile(patch, []byte("pvp=false\nview-distance=12\n"), 0644)
+
+	if err := Apply(patch, target); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, _ := ioutil.ReadFile(target)
+	expected := "# comment\nmotd=hi\npvp=false\nview-distance=12\n"
+	if string(raw) != expected {
+		t.Fatalf("expected %q, got %q", expected, raw)
+	}
+}
+
+func TestApplyJSON5(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "foo.json5")
+	patch := filepath.Join(dir, "foo.json5.patch.json")
+	ioutil.WriteFile(target, []byte(`// keybinds
+{
+  keybind: 'G', /* the default is H */
+  server: {
+    pvp: true, // no griefing
+    motd: "hi",
+  },
+  removed: [1, 2],
+}
+`), 0644)
+	ioutil.WriteFile(patch, []byte(`{"server": {"pvp": false, "port": 25565}, "removed": null, "fov": 90}`), 0644)
+
+	if err := Apply(patch, target); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, _ := ioutil.ReadFile(target)
+	expected := `// keybinds
+{
+  keybind: 'G', /* the default is H */
+  server: {
+    pvp: false, // no griefing
+    motd: "hi",
+    "port": 25565,
+  },
+  "fov": 90,
+}
+`
+	if string(raw) != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, raw)
+	}
+}
+
+func TestApplyJSONComments(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "foo.json")
+	patch := filepath.Join(dir, "foo.json.patch.toml")
+	ioutil.WriteFile(target, []byte("{\n  // render distance\n  \"distance\": 8,\n  \"gamma\": 1\n}\n"), 0644)
+	ioutil.WriteFile(patch, []byte("gamma = 2\n[sound]\nvolume = 0.5\n"), 0644)
+
+	if err := Apply(patch, target); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, _ := ioutil.ReadFile(target)
+	expected := "{\n  // render distance\n  \"distance\": 8,\n  \"gamma\": 2,\n  \"sound\": {\n    \"volume\": 0.5\n  }\n}\n"
+	if string(raw) != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, raw)
+	}
+}
+
+func TestApplyJSONRemoveLast(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "foo.json")
+	patch := filepath.Join(dir, "foo.json.patch.json")
+	ioutil.WriteFile(target, []byte("{\n  \"a\": 1,\n  \"b\": 2\n}\n"), 0644)
+	ioutil.WriteFile(patch, []byte(`{"b": null}`), 0644)
+
+	if err := Apply(patch, target); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, _ := ioutil.ReadFile(target)
+	if !json.Valid(raw) || string(raw) != "{\n  \"a\": 1\n}\n" {
+		t.Fatalf("unexpected patched content: %q", raw)
+	}
+}
+
+func TestApplyToml(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "foo.toml")
+	patch := filepath.Join(dir, "foo.toml.patch.json")
+	ioutil.WriteFile(target, []byte(`# general settings
+fov = 70 # degrees
+removed = "yes"
+
+[server]
+# player versus player
+pvp = true
+motd = "hi"
+
+[[servers]]
+name = "lobby"
+`), 0644)
+	ioutil.WriteFile(patch, []byte(`{"fov": 90, "removed": null, "gamma": 1.5, "server": {"pvp": false, "port": 25565}, "sound": {"volume": 1}}`), 0644)
+
+	if err := Apply(patch, target); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, _ := ioutil.ReadFile(target)
+	expected := `# general settings
+fov = 90 # degrees
+gamma = 1.5
+sound.volume = 1
+
+[server]
+# player versus player
+pvp = false
+motd = "hi"
+port = 25565
+
+[[servers]]
+name = "lobby"
+`
+	if string(raw) != expected {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, raw)
+	}
+}
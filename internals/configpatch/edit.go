@@ -0,0 +1,72 @@
+package configpatch
+
+import (
+	"bytes"
+	"sort"
+)
+
+// span is a range of bytes in a document
+type span struct {
+	start int
+	end   int
+}
+
+// edit replaces a span of a document with text. Empty spans insert text
+type edit struct {
+	span
+	text string
+}
+
+// applyEdits returns src with all edits applied. The edits must not overlap
+func applyEdits(src []byte, edits []edit) []byte {
+	sort.SliceStable(edits, func(a, b int) bool {
+		if edits[a].start != edits[b].start {
+			return edits[a].start > edits[b].start
+		}
+		// removals before insertions at the same offset
+		return edits[a].end > edits[b].end
+	})
+	out := append([]byte{}, src...)
+	for _, e := range edits {
+		out = append(out[:e.start], append([]byte(e.text), out[e.end:]...)...)
+	}
+	return out
+}
+
+// lineStart returns the offset of the line that contains pos
+func lineStart(src []byte, pos int) int {
+	return bytes.LastIndexByte(src[:pos], '\n') + 1
+}
+
+// lineEnd returns the offset after the newline of the line that contains pos (or the end of src)
+func lineEnd(src []byte, pos int) int {
+	end := bytes.IndexByte(src[pos:], '\n')
+	if end == -1 {
+		return len(src)
+	}
+	return pos + end + 1
+}
+
+// lineIndent returns the whitespace at the start of the line that contains pos
+func lineIndent(src []byte, pos int) string {
+	start := lineStart(src, pos)
+	end := start
+	for end < len(src) && (src[end] == ' ' || src[end] == '\t') {
+		end++
+	}
+	return string(src[start:end])
+}
+
+// startsLine returns true if there is only whitespace in front of pos on its line
+func startsLine(src []byte, pos int) bool {
+	return len(bytes.TrimSpace(src[lineStart(src, pos):pos])) == 0
+}
+
+// wholeLines extends start and end to whole lines if there is nothing else on them
+func wholeLines(src []byte, start int, end int) span {
+	next := lineEnd(src, end)
+	if startsLine(src, start) && len(bytes.TrimSpace(src[end:next])) == 0 {
+		return span{lineStart(src, start), next}
+	}
+	return span{start, end}
+}
@@ -0,0 +1,465 @@
+package configpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// jsonObject is an object of a JSON or JSON5 document with the offsets of its members.
+// Documents are patched in place with these offsets, so comments and the formatting are kept
+type jsonObject struct {
+	// start is the offset of the "{"
+	start   int
+	members []*jsonMember
+}
+
+type jsonMember struct {
+	key string
+	// start is the offset of the key
+	start      int
+	valueStart int
+	valueEnd   int
+	// commaEnd is the offset after the comma that follows the value or -1
+	commaEnd int
+	// object is set if the value is an object
+	object *jsonObject
+}
+
+// end returns the offset after the member including its comma
+func (m *jsonMember) end() int {
+	if m.commaEnd != -1 {
+		return m.commaEnd
+	}
+	return m.valueEnd
+}
+
+// jsonParser parses JSON5 (and with it JSON). Only the structure of objects is kept
+type jsonParser struct {
+	src []byte
+	pos int
+}
+
+// parseJSON5 parses a document that has to contain a single object
+func parseJSON5(src []byte) (*jsonObject, error) {
+	p := &jsonParser{src: src}
+	if err := p.skipSpace(); err != nil {
+		return nil, err
+	}
+	if p.pos >= len(src) || src[p.pos] != '{' {
+		return nil, errors.New("the file does not contain an object")
+	}
+	obj, err := p.object()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.skipSpace(); err != nil {
+		return nil, err
+	}
+	if p.pos != len(src) {
+		return nil, p.errorf("unexpected content after the object")
+	}
+	return obj, nil
+}
+
+func (p *jsonParser) errorf(format string, args ...interface{}) error {
+	line := bytes.Count(p.src[:p.pos], []byte("\n")) + 1
+	return fmt.Errorf("line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+// skipSpace skips whitespace and comments
+func (p *jsonParser) skipSpace() error {
+	for p.pos < len(p.src) {
+		rest := p.src[p.pos:]
+		switch {
+		case rest[0] == ' ' || rest[0] == '\t' || rest[0] == '\n' || rest[0] == '\r':
+			p.pos++
+		case bytes.HasPrefix(rest, []byte("//")):
+			end := bytes.IndexByte(rest, '\n')
+			if end == -1 {
+				p.pos = len(p.src)
+				return nil
+			}
+			p.pos += end + 1
+		case bytes.HasPrefix(rest, []byte("/*")):
+			end := bytes.Index(rest[2:], []byte("*/"))
+			if end == -1 {
+				return p.errorf("unterminated comment")
+			}
+			p.pos += end + 4
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// value parses any value. The object is returned if the value is one
+func (p *jsonParser) value() (*jsonObject, error) {
+	if err := p.skipSpace(); err != nil {
+		return nil, err
+	}
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("unexpected end of file")
+	}
+	switch p.src[p.pos] {
+	case '{':
+		return p.object()
+	case '[':
+		return nil, p.array()
+	case '"', '\'':
+		_, err := p.string()
+		return nil, err
+	}
+	return nil, p.scalar()
+}
+
+func (p *jsonParser) object() (*jsonObject, error) {
+	obj := &jsonObject{start: p.pos}
+	p.pos++
+	for {
+		if err := p.skipSpace(); err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("unterminated object")
+		}
+		if p.src[p.pos] == '}' {
+			p.pos++
+			return obj, nil
+		}
+
+		member := &jsonMember{start: p.pos, commaEnd: -1}
+		key, err := p.key()
+		if err != nil {
+			return nil, err
+		}
+		member.key = key
+		if err := p.skipSpace(); err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.src) || p.src[p.pos] != ':' {
+			return nil, p.errorf("expected \":\" after %q", key)
+		}
+		p.pos++
+		if err := p.skipSpace(); err != nil {
+			return nil, err
+		}
+		member.valueStart = p.pos
+		if member.object, err = p.value(); err != nil {
+			return nil, err
+		}
+		member.valueEnd = p.pos
+		obj.members = append(obj.members, member)
+
+		if err := p.skipSpace(); err != nil {
+			return nil, err
+		}
+		switch {
+		case p.pos < len(p.src) && p.src[p.pos] == ',':
+			p.pos++
+			member.commaEnd = p.pos
+		case p.pos >= len(p.src) || p.src[p.pos] != '}':
+			return nil, p.errorf("expected \",\" or \"}\" after %q", key)
+		}
+	}
+}
+
+func (p *jsonParser) array() error {
+	p.pos++
+	for {
+		if err := p.skipSpace(); err != nil {
+			return err
+		}
+		if p.pos >= len(p.src) {
+			return p.errorf("unterminated array")
+		}
+		if p.src[p.pos] == ']' {
+			p.pos++
+			return nil
+		}
+		if _, err := p.value(); err != nil {
+			return err
+		}
+		if err := p.skipSpace(); err != nil {
+			return err
+		}
+		switch {
+		case p.pos < len(p.src) && p.src[p.pos] == ',':
+			p.pos++
+		case p.pos >= len(p.src) || p.src[p.pos] != ']':
+			return p.errorf("expected \",\" or \"]\"")
+		}
+	}
+}
+
+// key parses a quoted key or an unquoted JSON5 identifier
+func (p *jsonParser) key() (string, error) {
+	if c := p.src[p.pos]; c == '"' || c == '\'' {
+		return p.string()
+	}
+	start := p.pos
+	for p.pos < len(p.src) && isIdentifierChar(p.src[p.pos]) {
+		p.pos++
+	}
+	if p.pos == start {
+		return "", p.errorf("unexpected character %q", p.src[p.pos])
+	}
+	return string(p.src[start:p.pos]), nil
+}
+
+// string parses a double or single quoted string and returns its content
+func (p *jsonParser) string() (string, error) {
+	quote := p.src[p.pos]
+	start := p.pos
+	p.pos++
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case '\\':
+			p.pos += 2
+		case quote:
+			p.pos++
+			return unescape(p.src[start+1 : p.pos-1]), nil
+		case '\n':
+			return "", p.errorf("unterminated string")
+		default:
+			p.pos++
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// scalar parses numbers, true, false, null and the JSON5 numbers Infinity and NaN
+func (p *jsonParser) scalar() error {
+	start := p.pos
+	for p.pos < len(p.src) && !isDelimiter(p.src[p.pos]) {
+		p.pos++
+	}
+	switch token := string(p.src[start:p.pos]); {
+	case token == "true", token == "false", token == "null":
+		return nil
+	case token != "" && strings.ContainsAny(token[:1], "0123456789+-.IN"):
+		return nil
+	}
+	p.pos = start
+	return p.errorf("unexpected character %q", p.src[p.pos])
+}
+
+func isIdentifierChar(c byte) bool {
+	return c == '_' || c == '$' || c >= 0x80 ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func isDelimiter(c byte) bool {
+	return strings.IndexByte(",:]} \t\r\n/", c) != -1
+}
+
+// unescape returns the content of a string with all escape sequences replaced
+func unescape(raw []byte) string {
+	if bytes.IndexByte(raw, '\\') == -1 {
+		return string(raw)
+	}
+	var out strings.Builder
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '\\' || i+1 == len(raw) {
+			out.WriteByte(raw[i])
+			continue
+		}
+		i++
+		switch raw[i] {
+		case 'n':
+			out.WriteByte('\n')
+		case 't':
+			out.WriteByte('\t')
+		case 'r':
+			out.WriteByte('\r')
+		case 'b':
+			out.WriteByte('\b')
+		case 'f':
+			out.WriteByte('\f')
+		case '0':
+			out.WriteByte(0)
+		case '\n':
+			// JSON5 line continuation
+		case 'u':
+			r, ok := unicodeEscape(raw[i+1:])
+			if !ok {
+				out.WriteString(`\u`)
+				continue
+			}
+			i += 4
+			if utf16.IsSurrogate(r) && bytes.HasPrefix(raw[i+1:], []byte(`\u`)) {
+				if low, ok := unicodeEscape(raw[i+3:]); ok {
+					r = utf16.DecodeRune(r, low)
+					i += 6
+				}
+			}
+			out.WriteRune(r)
+		default:
+			out.WriteByte(raw[i])
+		}
+	}
+	return out.String()
+}
+
+func unicodeEscape(raw []byte) (rune, bool) {
+	if len(raw) < 4 {
+		return 0, false
+	}
+	code, err := strconv.ParseUint(string(raw[:4]), 16, 16)
+	return rune(code), err == nil
+}
+
+// find returns all members with the given key (usually only one)
+func (o *jsonObject) find(key string) []*jsonMember {
+	found := make([]*jsonMember, 0, 1)
+	for _, member := range o.members {
+		if member.key == key {
+			found = append(found, member)
+		}
+	}
+	return found
+}
+
+func patchJSON(existing []byte, patch map[string]interface{}) ([]byte, error) {
+	if len(bytes.TrimSpace(existing)) == 0 {
+		encoded, err := encodeJSON(withoutNil(patch), "")
+		if err != nil {
+			return nil, err
+		}
+		return []byte(encoded + "\n"), nil
+	}
+
+	root, err := parseJSON5(existing)
+	if err != nil {
+		return nil, err
+	}
+	edits := make([]edit, 0)
+	if err := patchJSONObject(existing, root, patch, &edits); err != nil {
+		return nil, err
+	}
+	return applyEdits(existing, edits), nil
+}
+
+// patchJSONObject adds the edits that merge patch into obj
+func patchJSONObject(src []byte, obj *jsonObject, patch map[string]interface{}, edits *[]edit) error {
+	removed := make(map[*jsonMember]bool)
+	added := make([]string, 0)
+
+	for _, key := range sortedKeys(patch) {
+		value := patch[key]
+		patchMap, isMap := value.(map[string]interface{})
+		members := obj.find(key)
+
+		if len(members) == 0 {
+			if value == nil {
+				continue
+			}
+			encoded, err := encodeJSON(withoutNil(value), lineIndent(src, obj.start)+"  ")
+			if err != nil {
+				return err
+			}
+			encodedKey, _ := json.Marshal(key)
+			added = append(added, string(encodedKey)+": "+encoded)
+			continue
+		}
+
+		for _, member := range members {
+			switch {
+			case value == nil:
+				removed[member] = true
+				*edits = append(*edits, edit{wholeLines(src, member.start, member.end()), ""})
+			case isMap && member.object != nil:
+				if err := patchJSONObject(src, member.object, patchMap, edits); err != nil {
+					return err
+				}
+			default:
+				encoded, err := encodeJSON(withoutNil(value), lineIndent(src, member.start))
+				if err != nil {
+					return err
+				}
+				*edits = append(*edits, edit{span{member.valueStart, member.valueEnd}, encoded})
+			}
+		}
+	}
+
+	var last *jsonMember
+	for _, member := range obj.members {
+		if !removed[member] {
+			last = member
+		}
+	}
+	// documents with a comma after the last member keep it
+	trailingComma := len(obj.members) != 0 && obj.members[len(obj.members)-1].commaEnd != -1
+
+	switch {
+	case last == nil && len(added) != 0:
+		indent := lineIndent(src, obj.start)
+		text := "\n" + indent + "  " + strings.Join(added, ",\n"+indent+"  ")
+		if len(obj.members) == 0 {
+			text += "\n" + indent
+		}
+		*edits = append(*edits, edit{span{obj.start + 1, obj.start + 1}, text})
+	case last == nil:
+		// nothing left
+	case len(added) != 0:
+		sep := " "
+		if startsLine(src, last.start) {
+			sep = "\n" + lineIndent(src, last.start)
+		}
+		text := sep + strings.Join(added, ","+sep)
+		if trailingComma {
+			text += ","
+		}
+		if last.commaEnd == -1 {
+			text = "," + text
+		}
+		*edits = append(*edits, edit{span{last.end(), last.end()}, text})
+	case last.commaEnd != -1 && !trailingComma:
+		// the members after the last one were removed, so it must not end with a comma anymore
+		*edits = append(*edits, edit{span{last.commaEnd - 1, last.commaEnd}, ""})
+	}
+	return nil
+}
+
+// encodeJSON encodes value. Lines after the first one start with indent
+func encodeJSON(value interface{}, indent string) (string, error) {
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent(indent, "  ")
+	if err := enc.Encode(value); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// withoutNil returns value without the nil values of (nested) maps.
+// They only mark keys that should be removed
+func withoutNil(value interface{}) interface{} {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+	clean := make(map[string]interface{}, len(m))
+	for key, v := range m {
+		if v != nil {
+			clean[key] = withoutNil(v)
+		}
+	}
+	return clean
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,462 @@
+package configpatch
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+)
+
+// errNotInPlace is returned if a TOML document can not be patched in place
+// (eg. because a table is replaced by a value)
+var errNotInPlace = errors.New("can not patch in place")
+
+var bareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// tomlDocument has the offsets of the keys and tables of a TOML document.
+// Documents are patched in place with these offsets, so comments and the order are kept
+type tomlDocument struct {
+	src     []byte
+	entries []*tomlEntry
+	tables  []*tomlTable
+	// added are the new lines per table
+	added map[*tomlTable][]string
+}
+
+// tomlEntry is a `key = value` line
+type tomlEntry struct {
+	path       []string
+	lineStart  int
+	valueStart int
+	valueEnd   int
+	// lineEnd is the offset after the line the value ends on
+	lineEnd int
+}
+
+// tomlTable is the root table or a table with a header
+type tomlTable struct {
+	path []string
+	// array is true for arrays of tables and their subtables. They can not be patched
+	array bool
+	// end is the offset new keys are inserted at (after the last key or the header)
+	end    int
+	indent string
+}
+
+func parseToml(src []byte) (*tomlDocument, error) {
+	current := &tomlTable{}
+	doc := &tomlDocument{
+		src:    src,
+		tables: []*tomlTable{current},
+		added:  make(map[*tomlTable][]string),
+	}
+
+	for pos := 0; pos < len(src); {
+		end := lineEnd(src, pos)
+		line := bytes.TrimSpace(src[pos:end])
+		start := pos + bytes.Index(src[pos:end], line)
+		switch {
+		case len(line) == 0 || line[0] == '#':
+			pos = end
+		case line[0] == '[':
+			array := bytes.HasPrefix(line, []byte("[["))
+			keyStart := start + 1
+			if array {
+				keyStart++
+			}
+			path, keyEnd, err := parseTomlKey(src, keyStart)
+			if err != nil {
+				return nil, err
+			}
+			if keyEnd >= len(src) || src[keyEnd] != ']' {
+				return nil, fmt.Errorf("invalid table header %s", line)
+			}
+			current = &tomlTable{path: path, array: array || doc.inArray(path), end: end}
+			doc.tables = append(doc.tables, current)
+			pos = end
+		default:
+			path, keyEnd, err := parseTomlKey(src, start)
+			if err != nil {
+				return nil, err
+			}
+			if keyEnd >= len(src) || src[keyEnd] != '=' {
+				return nil, fmt.Errorf("expected \"=\" after %s", strings.Join(path, "."))
+			}
+			valueStart := skipBlank(src, keyEnd+1)
+			valueEnd, err := scanTomlValue(src, valueStart)
+			if err != nil {
+				return nil, err
+			}
+			entry := &tomlEntry{
+				path:       append(append([]string{}, current.path...), path...),
+				lineStart:  pos,
+				valueStart: valueStart,
+				valueEnd:   valueEnd,
+				lineEnd:    lineEnd(src, valueEnd),
+			}
+			if !current.array {
+				doc.entries = append(doc.entries, entry)
+			}
+			current.end = entry.lineEnd
+			current.indent = string(src[pos:start])
+			pos = entry.lineEnd
+		}
+	}
+	return doc, nil
+}
+
+// parseTomlKey parses a (dotted) key that starts at pos. The offset after the key is returned
+func parseTomlKey(src []byte, pos int) ([]string, int, error) {
+	path := make([]string, 0, 1)
+	for {
+		pos = skipBlank(src, pos)
+		if pos >= len(src) {
+			return nil, pos, errors.New("unexpected end of file")
+		}
+		switch src[pos] {
+		case '"', '\'':
+			end, err := scanTomlValue(src, pos)
+			if err != nil {
+				return nil, pos, err
+			}
+			key := string(src[pos+1 : end-1])
+			if src[pos] == '"' {
+				key = unescape(src[pos+1 : end-1])
+			}
+			path = append(path, key)
+			pos = end
+		default:
+			end := pos
+			for end < len(src) && isBareKeyChar(src[end]) {
+				end++
+			}
+			if end == pos {
+				return nil, pos, fmt.Errorf("unexpected character %q", src[pos])
+			}
+			path = append(path, string(src[pos:end]))
+			pos = end
+		}
+		pos = skipBlank(src, pos)
+		if pos >= len(src) || src[pos] != '.' {
+			return path, pos, nil
+		}
+		pos++
+	}
+}
+
+// scanTomlValue returns the offset after the value that starts at pos
+func scanTomlValue(src []byte, pos int) (int, error) {
+	rest := src[pos:]
+	switch {
+	case len(rest) == 0:
+		return pos, errors.New("missing value")
+	case bytes.HasPrefix(rest, []byte(`"""`)), bytes.HasPrefix(rest, []byte(`'''`)):
+		quote := rest[:3]
+		for i := 3; i < len(rest); i++ {
+			if rest[i] == '\\' && quote[0] == '"' {
+				i++
+				continue
+			}
+			if bytes.HasPrefix(rest[i:], quote) {
+				// up to two quotes are allowed in front of the closing delimiter
+				end := i + 3
+				for n := 0; n < 2 && end < len(rest) && rest[end] == quote[0]; n++ {
+					end++
+				}
+				return pos + end, nil
+			}
+		}
+		return pos, errors.New("unterminated string")
+	case rest[0] == '"' || rest[0] == '\'':
+		for i := 1; i < len(rest) && rest[i] != '\n'; i++ {
+			if rest[i] == '\\' && rest[0] == '"' {
+				i++
+				continue
+			}
+			if rest[i] == rest[0] {
+				return pos + i + 1, nil
+			}
+		}
+		return pos, errors.New("unterminated string")
+	case rest[0] == '[' || rest[0] == '{':
+		depth := 0
+		for i := 0; i < len(rest); {
+			switch rest[i] {
+			case '[', '{':
+				depth++
+			case ']', '}':
+				depth--
+				if depth == 0 {
+					return pos + i + 1, nil
+				}
+			case '"', '\'':
+				end, err := scanTomlValue(src, pos+i)
+				if err != nil {
+					return pos, err
+				}
+				i = end - pos
+				continue
+			case '#':
+				i = lineEnd(src, pos+i) - pos
+				continue
+			}
+			i++
+		}
+		return pos, errors.New("unterminated array or inline table")
+	}
+	end := bytes.IndexAny(rest, "#\n")
+	if end == -1 {
+		end = len(rest)
+	}
+	return pos + len(bytes.TrimRight(rest[:end], " \t\r")), nil
+}
+
+func isBareKeyChar(c byte) bool {
+	return c == '_' || c == '-' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func skipBlank(src []byte, pos int) int {
+	for pos < len(src) && (src[pos] == ' ' || src[pos] == '\t') {
+		pos++
+	}
+	return pos
+}
+
+func (d *tomlDocument) inArray(path []string) bool {
+	for _, table := range d.tables {
+		if table.array && hasPrefix(path, table.path) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *tomlDocument) entry(path []string) *tomlEntry {
+	for _, entry := range d.entries {
+		if equalPath(entry.path, path) {
+			return entry
+		}
+	}
+	return nil
+}
+
+// isTable returns true if a table header or keys below path exist
+func (d *tomlDocument) isTable(path []string) bool {
+	for _, table := range d.tables {
+		if hasPrefix(table.path, path) {
+			return true
+		}
+	}
+	for _, entry := range d.entries {
+		if len(entry.path) > len(path) && hasPrefix(entry.path, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// parent returns the table with the longest header path that path belongs to
+func (d *tomlDocument) parent(path []string) *tomlTable {
+	parent := d.tables[0]
+	for _, table := range d.tables {
+		if len(table.path) > len(parent.path) && len(table.path) < len(path) && hasPrefix(path, table.path) {
+			parent = table
+		}
+	}
+	return parent
+}
+
+// patch adds the edits that merge patch into the table at path
+func (d *tomlDocument) patch(path []string, patch map[string]interface{}, edits *[]edit) error {
+	for _, key := range sortedKeys(patch) {
+		value := patch[key]
+		full := append(append([]string{}, path...), key)
+		entry := d.entry(full)
+		isTable := d.isTable(full)
+		patchMap, isMap := value.(map[string]interface{})
+
+		switch {
+		case value == nil && entry != nil:
+			*edits = append(*edits, edit{span{entry.lineStart, entry.lineEnd}, ""})
+		case value == nil && isTable:
+			return errNotInPlace
+		case value == nil:
+			// nothing to remove
+		case isMap && isTable:
+			if err := d.patch(full, patchMap, edits); err != nil {
+				return err
+			}
+		case entry != nil && !isMap:
+			encoded, err := tomlValue(value)
+			if err != nil {
+				return err
+			}
+			*edits = append(*edits, edit{span{entry.valueStart, entry.valueEnd}, encoded})
+		case entry != nil || isTable:
+			// a value is replaced by a table or the other way round
+			return errNotInPlace
+		default:
+			if err := d.add(full, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// add adds a new key to the nearest table with a header. Nested tables are added as dotted keys
+func (d *tomlDocument) add(path []string, value interface{}) error {
+	parent := d.parent(path)
+	if parent.array {
+		return errNotInPlace
+	}
+	if m, ok := value.(map[string]interface{}); ok {
+		for _, key := range sortedKeys(m) {
+			if m[key] == nil {
+				continue
+			}
+			if err := d.add(append(append([]string{}, path...), key), m[key]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	encoded, err := tomlValue(value)
+	if err != nil {
+		return err
+	}
+	d.added[parent] = append(d.added[parent], parent.indent+tomlKey(path[len(parent.path):])+" = "+encoded+"\n")
+	return nil
+}
+
+// insertions returns the edits that insert the added keys
+func (d *tomlDocument) insertions() []edit {
+	edits := make([]edit, 0, len(d.added))
+	for table, lines := range d.added {
+		text := strings.Join(lines, "")
+		if table.end > 0 && d.src[table.end-1] != '\n' {
+			text = "\n" + text
+		}
+		edits = append(edits, edit{span{table.end, table.end}, text})
+	}
+	return edits
+}
+
+// tomlValue encodes a single value
+func tomlValue(value interface{}) (string, error) {
+	tree, err := toml.TreeFromMap(map[string]interface{}{"v": tomlNumbers(value)})
+	if err != nil {
+		return "", err
+	}
+	encoded, err := tree.Marshal()
+	if err != nil {
+		return "", err
+	}
+	line := strings.TrimSpace(string(encoded))
+	if !strings.HasPrefix(line, "v = ") || strings.Contains(line, "\n") {
+		// arrays of tables can not be written as a value
+		return "", errNotInPlace
+	}
+	return strings.TrimPrefix(line, "v = "), nil
+}
+
+// tomlNumbers converts whole numbers from JSON patches to integers, so `12` is not written as `12.0`
+func tomlNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return int64(v)
+		}
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for i, item := range v {
+			converted[i] = tomlNumbers(item)
+		}
+		return converted
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[key] = tomlNumbers(item)
+		}
+		return converted
+	}
+	return value
+}
+
+func tomlKey(path []string) string {
+	keys := make([]string, len(path))
+	for i, key := range path {
+		keys[i] = key
+		if !bareKey.MatchString(key) {
+			keys[i] = strconv.Quote(key)
+		}
+	}
+	return strings.Join(keys, ".")
+}
+
+func patchToml(existing []byte, patch map[string]interface{}) ([]byte, error) {
+	if len(bytes.TrimSpace(existing)) == 0 {
+		return marshalToml(nil, patch)
+	}
+	tree, err := toml.LoadBytes(existing)
+	if err != nil {
+		return nil, err
+	}
+
+	if patched, err := patchTomlInPlace(existing, patch); err == nil {
+		return patched, nil
+	}
+	// the file is written as a whole. This loses comments and the order of the keys
+	return marshalToml(tree.ToMap(), patch)
+}
+
+func patchTomlInPlace(existing []byte, patch map[string]interface{}) ([]byte, error) {
+	doc, err := parseToml(existing)
+	if err != nil {
+		return nil, err
+	}
+	edits := make([]edit, 0)
+	if err := doc.patch(nil, patch, &edits); err != nil {
+		return nil, err
+	}
+	patched := applyEdits(existing, append(edits, doc.insertions()...))
+	// make sure the result is still valid
+	if _, err := toml.LoadBytes(patched); err != nil {
+		return nil, err
+	}
+	return patched, nil
+}
+
+func marshalToml(content map[string]interface{}, patch map[string]interface{}) ([]byte, error) {
+	if content == nil {
+		content = make(map[string]interface{})
+	}
+	merge(content, patch)
+	tree, err := toml.TreeFromMap(tomlNumbers(content).(map[string]interface{}))
+	if err != nil {
+		return nil, err
+	}
+	return tree.Marshal()
+}
+
+func hasPrefix(path []string, prefix []string) bool {
+	return len(path) >= len(prefix) && equalPath(path[:len(prefix)], prefix)
+}
+
+func equalPath(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
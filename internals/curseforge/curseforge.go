@@ -0,0 +1,153 @@
+// Package curseforge writes CurseForge modpack zips
+package curseforge
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/minepkg/minepkg/internals/bundle"
+	"github.com/minepkg/minepkg/internals/instances"
+)
+
+const (
+	// ManifestFile is the name of the manifest inside the pack
+	ManifestFile = "manifest.json"
+	// OverridesDir contains files that are copied into the minecraft directory
+	OverridesDir = "overrides"
+)
+
+// Manifest is the content of the `manifest.json`
+type Manifest struct {
+	Minecraft       Minecraft `json:"minecraft"`
+	ManifestType    string    `json:"manifestType"`
+	ManifestVersion int       `json:"manifestVersion"`
+	Name            string    `json:"name"`
+	Version         string    `json:"version"`
+	Author          string    `json:"author"`
+	Files           []*File   `json:"files"`
+	Overrides       string    `json:"overrides"`
+}
+
+// Minecraft contains the Minecraft version and mod loaders
+type Minecraft struct {
+	Version    string       `json:"version"`
+	ModLoaders []*ModLoader `json:"modLoaders"`
+}
+
+// ModLoader is a mod loader like "fabric-0.11.6" or "forge-36.2.0"
+type ModLoader struct {
+	ID      string `json:"id"`
+	Primary bool   `json:"primary"`
+}
+
+// File is a file hosted on CurseForge
+type File struct {
+	ProjectID int  `json:"projectID"`
+	FileID    int  `json:"fileID"`
+	Required  bool `json:"required"`
+}
+
+// FileFromURL returns the file for a CurseForge download url that contains the project and file id
+// (eg. "https://www.curseforge.com/api/v1/mods/238222/files/4371817/download").
+// The second return value is false for all other urls
+func FileFromURL(rawURL string) (*File, bool) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Host != "curseforge.com" && !strings.HasSuffix(parsed.Host, ".curseforge.com")) {
+		return nil, false
+	}
+	// the path is ".../mods/<project id>/files/<file id>/download"
+	parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	for n := 0; n+3 < len(parts); n++ {
+		if parts[n] != "mods" || parts[n+2] != "files" {
+			continue
+		}
+		projectID, err := strconv.Atoi(parts[n+1])
+		if err != nil {
+			return nil, false
+		}
+		fileID, err := strconv.Atoi(parts[n+3])
+		if err != nil {
+			return nil, false
+		}
+		return &File{ProjectID: projectID, FileID: fileID, Required: true}, true
+	}
+	return nil, false
+}
+
+// NewManifest returns the CurseForge manifest for the given instance.
+// Locked mods with a CurseForge download url are added to `files` (see `FileFromURL`)
+func NewManifest(instance *instances.Instance) *Manifest {
+	man := instance.Manifest
+	cfManifest := &Manifest{
+		Minecraft: Minecraft{
+			Version:    instance.Lockfile.MinecraftVersion(),
+			ModLoaders: make([]*ModLoader, 0, 1),
+		},
+		ManifestType:    "minecraftModpack",
+		ManifestVersion: 1,
+		Name:            man.Package.Name,
+		Version:         man.Package.Version,
+		Author:          man.AuthorName(),
+		Files:           make([]*File, 0),
+		Overrides:       OverridesDir,
+	}
+
+	platform := instance.Lockfile.PlatformLock()
+	if platform != nil && platform.PlatformVersion() != "" {
+		cfManifest.Minecraft.ModLoaders = append(cfManifest.Minecraft.ModLoaders, &ModLoader{
+			ID:      platform.PlatformName() + "-" + platform.PlatformVersion(),
+			Primary: true,
+		})
+	}
+
+	for _, dep := range instance.Lockfile.Dependencies {
+		if file, ok := FileFromURL(dep.URL); ok && dep.InstallPath() != "" {
+			cfManifest.Files = append(cfManifest.Files, file)
+		}
+	}
+	return cfManifest
+}
+
+// Export writes the prepared instance as a CurseForge modpack.
+// The CurseForge format can only reference files hosted on CurseForge by their ids,
+// so all other locked mods are placed in `overrides/mods` together with the overwrites.
+// Data packs are placed in the worlds they are linked to (see `Instance.InstallPaths`)
+func Export(w io.Writer, instance *instances.Instance) error {
+	b := bundle.NewWriter(w, instance.CacheDir)
+
+	buf, err := json.MarshalIndent(NewManifest(instance), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := b.AddBytes(ManifestFile, buf); err != nil {
+		return err
+	}
+
+	for _, dep := range instance.Lockfile.Dependencies {
+		if dep.URL == "" {
+			continue
+		}
+		if _, ok := FileFromURL(dep.URL); ok {
+			continue
+		}
+		paths, err := instance.InstallPaths(dep)
+		if err != nil {
+			return err
+		}
+		src := filepath.Join(instance.PackageCacheDir(), dep.Name, dep.Version+dep.FileExt())
+		for _, path := range paths {
+			if err := b.AddFile(OverridesDir+"/"+path, src); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := b.AddMinecraftFiles(OverridesDir, instance); err != nil {
+		return err
+	}
+	return b.Close()
+}
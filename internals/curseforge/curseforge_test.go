@@ -0,0 +1,38 @@
+package curseforge
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFileFromURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want *File
+	}{
+		{
+			"website download",
+			"https://www.curseforge.com/api/v1/mods/238222/files/4371817/download",
+			&File{ProjectID: 238222, FileID: 4371817, Required: true},
+		},
+		{
+			"cdn without project id",
+			"https://edge.forgecdn.net/files/4371/817/jei.jar",
+			nil,
+		},
+		{
+			"other host",
+			"https://example.com/mods/1/files/2/download",
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := FileFromURL(tt.url)
+			if ok != (tt.want != nil) || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FileFromURL() = %+v, %v, want %+v", got, ok, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package downloadmgr
+
+import (
+	"encoding/json"
+)
+
+// EventType describes what happened to a queued item
+type EventType uint8
+
+const (
+	// EventQueued is emitted for every item when the download queue starts
+	EventQueued EventType = iota
+	// EventStarted is emitted when an item starts downloading (also on every retry)
+	EventStarted
+	// EventProgress is emitted periodically while an item is transferring bytes
+	EventProgress
+	// EventRetry is emitted when an attempt failed and the item will be retried
+	EventRetry
+	// EventCompleted is emitted when an item was downloaded successfully
+	EventCompleted
+	// EventFailed is emitted when an item failed and will not be retried anymore
+	EventFailed
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventQueued:
+		return "queued"
+	case EventStarted:
+		return "started"
+	case EventProgress:
+		return "progress"
+	case EventRetry:
+		return "retry"
+	case EventCompleted:
+		return "completed"
+	case EventFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalJSON encodes the event type as its string representation
+func (t EventType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// Event is a single progress update for one item of the download queue
+type Event struct {
+	Type EventType `json:"type"`
+	// ID is the position of the item in the queue
+	ID int `json:"id"`
+	// Item is the downloader this event belongs to
+	Item Downloader `json:"-"`
+	// Target is the path the item is downloaded to (if known)
+	Target string `json:"target,omitempty"`
+	// Transferred is the number of bytes transferred in the current attempt
+	Transferred int64 `json:"transferred"`
+	// Total is the expected size in bytes. 0 if unknown
+	Total int64 `json:"total"`
+	// Attempt is the number of the current attempt (starting with 1)
+	Attempt uint16 `json:"attempt"`
+	// Err is the reason for a retry or failure
+	Err error `json:"-"`
+}
+
+// MarshalJSON encodes the event including the error message (if any)
+func (e *Event) MarshalJSON() ([]byte, error) {
+	type plainEvent Event
+	errMsg := ""
+	if e.Err != nil {
+		errMsg = e.Err.Error()
+	}
+	return json.Marshal(&struct {
+		*plainEvent
+		Error string `json:"error,omitempty"`
+	}{(*plainEvent)(e), errMsg})
+}
+
+// ProgressReporter can be implemented by a Downloader to report transferred bytes
+type ProgressReporter interface {
+	// Progress returns the transferred and total bytes of the current attempt
+	Progress() (transferred int64, total int64)
+}
+
+// Targeter can be implemented by a Downloader to report its target path
+type Targeter interface {
+	TargetPath() string
+}
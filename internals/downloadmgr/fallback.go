@@ -0,0 +1,58 @@
+package downloadmgr
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// ErrNoDownloaders is returned when a FallbackItem has no downloaders
+var ErrNoDownloaders = errors.New("no downloaders to try")
+
+// FallbackItem tries each of its downloaders in order until one succeeds.
+// All downloaders should download to the same target
+type FallbackItem struct {
+	Downloaders []Downloader
+	current     int32
+}
+
+// NewFallbackItem returns a new FallbackItem trying the given downloaders in order
+func NewFallbackItem(downloaders ...Downloader) *FallbackItem {
+	return &FallbackItem{Downloaders: downloaders}
+}
+
+// Download tries all downloaders in order and returns the error of the last one if all failed
+func (f *FallbackItem) Download(ctx context.Context) error {
+	err := ErrNoDownloaders
+	for n, downloader := range f.Downloaders {
+		atomic.StoreInt32(&f.current, int32(n))
+		err = downloader.Download(ctx)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func (f *FallbackItem) currentDownloader() Downloader {
+	if len(f.Downloaders) == 0 {
+		return nil
+	}
+	return f.Downloaders[atomic.LoadInt32(&f.current)]
+}
+
+// Progress returns the progress of the downloader that is currently tried
+func (f *FallbackItem) Progress() (int64, int64) {
+	if reporter, ok := f.currentDownloader().(ProgressReporter); ok {
+		return reporter.Progress()
+	}
+	return 0, 0
+}
+
+// TargetPath returns the target of the downloader that is currently tried
+func (f *FallbackItem) TargetPath() string {
+	if targeter, ok := f.currentDownloader().(Targeter); ok {
+		return targeter.TargetPath()
+	}
+	return ""
+}
@@ -2,14 +2,20 @@ package downloadmgr
 
 import (
 	"context"
+	"crypto/sha1"
 	"crypto/sha256"
 	"fmt"
+	"hash"
 	"io"
 	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"time"
+
+	"github.com/minepkg/minepkg/internals/lancache"
+	"github.com/minepkg/minepkg/internals/mirrors"
 )
 
 var defaultClient = http.Client{
@@ -27,38 +33,62 @@ var defaultClient = http.Client{
 // HTTPItem is a URL, target pair with optional properties that will be downloaded
 // using http(s)
 type HTTPItem struct {
-	Client           *http.Client
-	URL              string
+	Client *http.Client
+	// Method is the http method used for the request. defaults to GET
+	Method string
+	URL    string
+	// FallbackURLs are tried in order if downloading from URL fails
+	FallbackURLs     []string
 	Target           string
 	Size             int
 	Sha256           string
+	Sha1             string
 	bytesTransferred int64
+	bytesTotal       int64
 }
 
-// ErrInvalidSha is returned when the downloaded file's sha256 sum does not match the given sha1
+// ErrInvalidSha is returned when the downloaded file's sha256 or sha1 sum does not match the given one
 type ErrInvalidSha struct {
 	FileName    string
 	ExpectedSha string
 	ActualSha   string
+	// Algorithm is either "sha256" or "sha1"
+	Algorithm string
 }
 
 func (e *ErrInvalidSha) Error() string {
 	return fmt.Sprintf(
-		"File corrupted: %s sha256 is invalid.\n\texpected to be \"%s\"\n\tbut actually is \"%s\"\n",
+		"File corrupted: %s %s is invalid.\n\texpected to be \"%s\"\n\tbut actually is \"%s\"\n",
 		e.FileName,
+		e.Algorithm,
 		e.ExpectedSha,
 		e.ActualSha,
 	)
 }
 
-// Download downloads the item to the defined target using http
+// Download downloads the item to the defined target using http.
+// FallbackURLs are tried in order if the download from URL fails
 func (i *HTTPItem) Download(ctx context.Context) error {
 	err := os.MkdirAll(filepath.Dir(i.Target), os.ModePerm)
 	if err != nil {
 		return err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", i.URL, nil)
+	for _, fallback := range append([]string{i.URL}, i.FallbackURLs...) {
+		err = i.downloadFrom(ctx, fallback)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func (i *HTTPItem) downloadFrom(ctx context.Context, URL string) error {
+	method := i.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	req, err := http.NewRequestWithContext(ctx, method, URL, nil)
 	if err != nil {
 		return err
 	}
@@ -70,7 +100,7 @@ func (i *HTTPItem) Download(ctx context.Context) error {
 
 	fileRes, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("Error while fetching %s: %w", i.URL, err)
+		return fmt.Errorf("Error while fetching %s: %w", URL, err)
 	}
 	defer fileRes.Body.Close()
 
@@ -78,6 +108,13 @@ func (i *HTTPItem) Download(ctx context.Context) error {
 		return fmt.Errorf("invalid status code: %s from %s", fileRes.Status, fileRes.Request.URL)
 	}
 
+	atomic.StoreInt64(&i.bytesTransferred, 0)
+	total := fileRes.ContentLength
+	if total < 0 {
+		total = int64(i.Size)
+	}
+	atomic.StoreInt64(&i.bytesTotal, total)
+
 	dest, err := os.Create(i.Target)
 	if err != nil {
 		return err
@@ -99,10 +136,16 @@ func (i *HTTPItem) Download(ctx context.Context) error {
 			return err
 		}
 	}
+	if i.Sha1 != "" {
+		if err := CheckSha1(i.Sha1, dest.Name()); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-// NewHTTPItem creates a Item to be queued that will download the file using HTTP(S)
+// NewHTTPItem creates a Item to be queued that will download the file using HTTP(S).
+// A configured LAN cache peer and mirrors for the URL's host are tried first
 func NewHTTPItem(URL string, Target string) *HTTPItem {
 	if URL == "" {
 		panic("Download URL can not be empty")
@@ -110,16 +153,47 @@ func NewHTTPItem(URL string, Target string) *HTTPItem {
 	if Target == "" {
 		panic("Target can not be empty")
 	}
-	return &HTTPItem{&defaultClient, URL, Target, 0, "", 0}
+	urls := mirrors.Default.URLs(URL)
+	if peer := lancache.DefaultPeer; peer != nil {
+		// the peer is pinged on the first download it could serve
+		if peerURL, ok := peer.URLFor(Target); ok && peer.Reachable() {
+			urls = append([]string{peerURL}, urls...)
+		}
+	}
+	return &HTTPItem{
+		Client:       &defaultClient,
+		URL:          urls[0],
+		FallbackURLs: urls[1:],
+		Target:       Target,
+	}
+}
+
+// Progress returns the transferred and total bytes of the current download attempt
+func (i *HTTPItem) Progress() (int64, int64) {
+	return atomic.LoadInt64(&i.bytesTransferred), atomic.LoadInt64(&i.bytesTotal)
+}
+
+// TargetPath returns the path this item is downloaded to
+func (i *HTTPItem) TargetPath() string {
+	return i.Target
 }
 
 func checkSha256(sha string, srcPath string) error {
+	return checkSha(sha256.New(), "sha256", sha, srcPath)
+}
+
+// CheckSha1 returns ErrInvalidSha (and removes the file) if the sha1 sum of the file at srcPath
+// does not match the given sha
+func CheckSha1(sha string, srcPath string) error {
+	return checkSha(sha1.New(), "sha1", sha, srcPath)
+}
+
+func checkSha(hasher hash.Hash, algorithm string, sha string, srcPath string) error {
 	src, err := os.Open(srcPath)
 	if err != nil {
 		return err
 	}
 	defer src.Close()
-	hasher := sha256.New()
 	_, err = io.Copy(hasher, src)
 	// probably io error during hashing
 	if err != nil {
@@ -129,7 +203,7 @@ func checkSha256(sha string, srcPath string) error {
 	if actualSha != sha {
 		// TODO: this can fail! move file to tmp storage first
 		os.Remove(src.Name())
-		return &ErrInvalidSha{src.Name(), sha, actualSha}
+		return &ErrInvalidSha{src.Name(), sha, actualSha, algorithm}
 	}
 	return nil
 }
@@ -144,6 +218,6 @@ type WriteCounter struct {
 // Always completes and never returns an error.
 func (wc *WriteCounter) Write(p []byte) (int, error) {
 	n := len(p)
-	*wc.Total += int64(n)
+	atomic.AddInt64(wc.Total, int64(n))
 	return n, nil
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sync"
 	"time"
 )
 
@@ -19,27 +20,96 @@ func (e *ErrFailedAttempt) Error() string {
 	return e.err.Error()
 }
 
+func (e *ErrFailedAttempt) Unwrap() error {
+	return e.err
+}
+
+// progressInterval is the interval in which EventProgress is emitted
+var progressInterval = 250 * time.Millisecond
+
 // DownloadManager includes a queue to download
 type DownloadManager struct {
 	queue      []*Item
 	OnProgress func(p int)
+	// OnEvent receives structured events for every item in the queue.
+	// It is never called concurrently. Retry errors are printed to stderr if this is nil
+	OnEvent func(e *Event)
+
+	eventMu sync.Mutex
 }
 
 type Item struct {
+	id          int
 	downloader  Downloader
 	lastErr     error
 	attempts    uint16
 	maxAttempts uint16
+	// running and attempt are guarded by eventMu, they are read by the progress reporter
+	running bool
+	attempt uint16
 }
 
 // Add adds a new item to the queue
 func (d *DownloadManager) Add(i Downloader) {
 	d.queue = append(d.queue, &Item{
+		id:          len(d.queue),
 		downloader:  i,
 		maxAttempts: 12,
 	})
 }
 
+// emit passes a new event for the given item to OnEvent. attempt starts at 1
+func (d *DownloadManager) emit(t EventType, item *Item, attempt uint16, err error) {
+	if d.OnEvent == nil {
+		return
+	}
+	d.eventMu.Lock()
+	defer d.eventMu.Unlock()
+
+	event := &Event{
+		Type:    t,
+		ID:      item.id,
+		Item:    item.downloader,
+		Attempt: attempt,
+		Err:     err,
+	}
+	if targeter, ok := item.downloader.(Targeter); ok {
+		event.Target = targeter.TargetPath()
+	}
+	if reporter, ok := item.downloader.(ProgressReporter); ok {
+		event.Transferred, event.Total = reporter.Progress()
+	}
+	d.OnEvent(event)
+}
+
+// reportProgress emits EventProgress for all running items until ctx is done
+func (d *DownloadManager) reportProgress(ctx context.Context) {
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, item := range d.queue {
+				d.eventMu.Lock()
+				running, attempt := item.running, item.attempt
+				d.eventMu.Unlock()
+				if running {
+					d.emit(EventProgress, item, attempt, nil)
+				}
+			}
+		}
+	}
+}
+
+func (d *DownloadManager) setRunning(item *Item, running bool, attempt uint16) {
+	d.eventMu.Lock()
+	item.running = running
+	item.attempt = attempt
+	d.eventMu.Unlock()
+}
+
 // Start starts the download queue
 func (d *DownloadManager) Start(ctx context.Context) error {
 	sem := make(chan int, 16)
@@ -49,14 +119,29 @@ func (d *DownloadManager) Start(ctx context.Context) error {
 		return nil
 	}
 
+	for _, item := range d.queue {
+		d.emit(EventQueued, item, 1, nil)
+	}
+
+	if d.OnEvent != nil {
+		progressCtx, stopProgress := context.WithCancel(ctx)
+		defer stopProgress()
+		go d.reportProgress(progressCtx)
+	}
+
 	go func() {
 		for _, item := range d.queue {
 			sem <- 1
 			go func(item *Item, errc chan error) {
 				for {
 					time.Sleep(time.Duration(item.attempts*item.attempts) * time.Second)
+					attempt := item.attempts + 1
+					d.emit(EventStarted, item, attempt, nil)
+					d.setRunning(item, true, attempt)
 					err := item.downloader.Download(ctx)
+					d.setRunning(item, false, attempt)
 					if err == nil {
+						d.emit(EventCompleted, item, attempt, nil)
 						errc <- nil
 						break
 					}
@@ -64,9 +149,11 @@ func (d *DownloadManager) Start(ctx context.Context) error {
 
 					item.attempts += 1
 					if item.attempts >= item.maxAttempts {
+						d.emit(EventFailed, item, attempt, err)
 						errc <- err
 						break
 					} else {
+						d.emit(EventRetry, item, attempt, err)
 						errc <- &ErrFailedAttempt{err}
 					}
 				}
@@ -82,7 +169,9 @@ func (d *DownloadManager) Start(ctx context.Context) error {
 		if maybeErr != nil {
 			if errors.As(maybeErr, &attemptType) {
 				i--
-				fmt.Fprintf(os.Stderr, "! %s\n", maybeErr.Error())
+				if d.OnEvent == nil {
+					fmt.Fprintf(os.Stderr, "! %s\n", maybeErr.Error())
+				}
 			} else {
 				return maybeErr
 			}
@@ -0,0 +1,96 @@
+package downloadmgr
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDownloadManager_OnEvent(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// first request fails to trigger a retry
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte("hello"))
+	}))
+	defer srv.Close()
+
+	mgr := New()
+	mgr.Add(NewHTTPItem(srv.URL, filepath.Join(t.TempDir(), "hello.txt")))
+
+	events := []*Event{}
+	mgr.OnEvent = func(e *Event) {
+		events = append(events, e)
+	}
+
+	if err := mgr.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	wanted := []EventType{EventQueued, EventStarted, EventRetry, EventStarted, EventCompleted}
+	got := []EventType{}
+	for _, e := range events {
+		// progress events depend on timing
+		if e.Type != EventProgress {
+			got = append(got, e.Type)
+		}
+	}
+	if len(got) != len(wanted) {
+		t.Fatalf("expected events %v, got %v", wanted, got)
+	}
+	for i := range wanted {
+		if got[i] != wanted[i] {
+			t.Fatalf("expected events %v, got %v", wanted, got)
+		}
+	}
+
+	last := events[len(events)-1]
+	if last.Transferred != 5 || last.Total != 5 {
+		t.Fatalf("expected 5/5 bytes, got %d/%d", last.Transferred, last.Total)
+	}
+	if last.Attempt != 2 {
+		t.Fatalf("expected attempt 2, got %d", last.Attempt)
+	}
+	if events[2].Err == nil {
+		t.Fatal("expected retry event to contain the reason")
+	}
+}
+
+func TestFallbackItem_Download(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/corrupt":
+			w.Write([]byte("corrupt"))
+		case "/hello":
+			w.Write([]byte("hello"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	target := filepath.Join(t.TempDir(), "hello.txt")
+	// sha256 of "hello"
+	sha := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	item := NewFallbackItem(
+		&HTTPItem{URL: srv.URL + "/missing", Target: target, Sha256: sha},
+		&HTTPItem{URL: srv.URL + "/corrupt", Target: target, Sha256: sha},
+		&HTTPItem{URL: srv.URL + "/hello", Target: target, Sha256: sha},
+	)
+
+	if err := item.Download(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if item.TargetPath() != target {
+		t.Errorf("unexpected target %s", item.TargetPath())
+	}
+	if transferred, _ := item.Progress(); transferred != 5 {
+		t.Errorf("expected 5 transferred bytes, got %d", transferred)
+	}
+}
@@ -1,5 +1,14 @@
 package fabric
 
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+)
+
+// ErrNoManifest is returned if a jar does not contain a fabric.mod.json
+var ErrNoManifest = errors.New("jar does not contain a fabric.mod.json")
+
 type Manifest struct {
 	SchemaVersion int    `json:"schemaVersion"`
 	ID            string `json:"id"`
@@ -26,3 +35,29 @@ type Manifest struct {
 	Depends          map[string]string `json:"depends,omitempty"`
 	Custom           interface{}       `json:"custom,omitempty"`
 }
+
+// ReadJarManifest reads the fabric.mod.json of a mod jar
+func ReadJarManifest(jar string) (*Manifest, error) {
+	r, err := zip.OpenReader(jar)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if f.Name != "fabric.mod.json" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		man := &Manifest{}
+		if err := json.NewDecoder(rc).Decode(man); err != nil {
+			return nil, err
+		}
+		return man, nil
+	}
+	return nil, ErrNoManifest
+}
@@ -0,0 +1,171 @@
+// Package ignore matches paths against gitignore style patterns (used for `.minepkgignore`)
+package ignore
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Filename is the name of the ignore file in the package directory
+const Filename = ".minepkgignore"
+
+type rule struct {
+	pattern *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// Matcher decides if a path is ignored. The last matching pattern wins
+type Matcher struct {
+	rules []rule
+}
+
+// New returns a matcher for the given gitignore style patterns
+func New(patterns ...string) (*Matcher, error) {
+	m := &Matcher{}
+	if err := m.Add(patterns...); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Parse reads patterns (one per line) from r
+func Parse(r io.Reader) (*Matcher, error) {
+	patterns := make([]string, 0)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		patterns = append(patterns, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return New(patterns...)
+}
+
+// Load reads the patterns of the ignore file at path. They are added after the defaults, so the
+// file can negate them. A missing file results in a matcher with only the defaults
+func Load(path string, defaults ...string) (*Matcher, error) {
+	m, err := New(defaults...)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return m, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	custom, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	m.rules = append(m.rules, custom.rules...)
+	return m, nil
+}
+
+// Add appends patterns to the matcher. Empty lines and comments (starting with `#`) are skipped
+func (m *Matcher) Add(patterns ...string) error {
+	for _, pattern := range patterns {
+		pattern = strings.TrimRight(pattern, " \t\r")
+		if pattern == "" || strings.HasPrefix(pattern, "#") {
+			continue
+		}
+
+		r := rule{}
+		if strings.HasPrefix(pattern, "!") {
+			r.negate = true
+			pattern = pattern[1:]
+		}
+		pattern = strings.TrimPrefix(pattern, `\`)
+		if strings.HasSuffix(pattern, "/") {
+			r.dirOnly = true
+			pattern = strings.TrimRight(pattern, "/")
+		}
+
+		// patterns without a slash match at any depth
+		anchored := strings.Contains(pattern, "/")
+		pattern = strings.TrimPrefix(pattern, "/")
+
+		expr := "^"
+		if !anchored {
+			expr += "(?:.*/)?"
+		}
+		expr += translate(pattern) + "$"
+		compiled, err := regexp.Compile(expr)
+		if err != nil {
+			return fmt.Errorf("invalid ignore pattern %q: %w", pattern, err)
+		}
+		r.pattern = compiled
+		m.rules = append(m.rules, r)
+	}
+	return nil
+}
+
+// translate converts a glob pattern to a regular expression
+func translate(pattern string) string {
+	var expr strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case c == '*':
+			expr.WriteString("[^/]*")
+		case c == '?':
+			expr.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end == -1 {
+				expr.WriteString(`\[`)
+				continue
+			}
+			class := pattern[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expr.WriteString("[" + class + "]")
+			i += end
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return expr.String()
+}
+
+// Match returns true if the relative path is ignored. A path is also ignored if one of its
+// parent directories is ignored
+func (m *Matcher) Match(path string, isDir bool) bool {
+	path = strings.Trim(filepath.ToSlash(path), "/")
+	parts := strings.Split(path, "/")
+	for i := 1; i < len(parts); i++ {
+		if m.match(strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+	return m.match(path, isDir)
+}
+
+func (m *Matcher) match(path string, isDir bool) bool {
+	ignored := false
+	for _, r := range m.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		if r.pattern.MatchString(path) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
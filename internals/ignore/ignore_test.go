@@ -0,0 +1,44 @@
+package ignore
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	m, err := Parse(strings.NewReader(`
+# comment
+logs/
+.idea
+/build
+*.log
+!keep.log
+config/**/secret.json
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{"logs", true, true},
+		{"logs/latest.txt", false, true},
+		{"logs", false, false},
+		{"sub/.idea/workspace.xml", false, true},
+		{"build/out.jar", false, true},
+		{"sub/build/out.jar", false, false},
+		{"crash.log", false, true},
+		{"keep.log", false, false},
+		{"config/a/b/secret.json", false, true},
+		{"config/secret.json", false, true},
+		{"config/mod.json", false, false},
+	}
+	for _, test := range tests {
+		if ignored := m.Match(test.path, test.isDir); ignored != test.ignored {
+			t.Errorf("%s: expected ignored to be %v", test.path, test.ignored)
+		}
+	}
+}
@@ -2,14 +2,19 @@ package instances
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
 	"runtime"
 
 	"github.com/minepkg/minepkg/internals/downloadmgr"
+	"github.com/minepkg/minepkg/internals/ipfs"
 	"github.com/minepkg/minepkg/internals/pack"
 	"github.com/minepkg/minepkg/internals/resolver"
+	"github.com/minepkg/minepkg/internals/resolver/providers"
 	"github.com/minepkg/minepkg/pkg/manifest"
 )
 
@@ -39,6 +44,19 @@ func (i *Instance) GetResolver(ctx context.Context) (*resolver.Resolver, error)
 	res.IncludeDev = i.isFromWd
 	// res.AlsoDownload = true
 
+	// other workspace members are resolved from source
+	if i.workspace != nil {
+		members := make(map[string]*manifest.Manifest)
+		for _, member := range i.workspace.Members {
+			if member.Name() == i.Manifest.Package.Name {
+				continue
+			}
+			members[member.Name()] = member.Manifest
+			res.Replace[member.Name()] = "workspace"
+		}
+		res.Providers["workspace"] = &providers.WorkspaceProvider{Members: members}
+	}
+
 	return res, nil
 }
 
@@ -82,72 +100,275 @@ func (i *Instance) FindMissingDependencies() ([]*manifest.DependencyLock, error)
 	return missing, nil
 }
 
-// LinkDependencies links or copies all missing dependencies into the mods folder
+// LinkDependencies links or copies all missing dependencies into the mods folder.
+// Resource, shader and data packs are linked into their folders.
+// Only files that were placed by minepkg are replaced (see `State`), files added by the player are kept
 func (i *Instance) LinkDependencies() error {
-	files, err := ioutil.ReadDir(i.ModsDir())
+	if err := os.MkdirAll(i.ModsDir(), os.ModePerm); err != nil {
+		return err
+	}
+	state, err := i.LoadState()
 	if err != nil {
-		if os.IsNotExist(err) {
-			if err := os.MkdirAll(i.ModsDir(), os.ModePerm); err != nil {
-				return err
-			}
-		} else {
+		return err
+	}
+
+	for _, name := range state.Mods {
+		if err := os.Remove(filepath.Join(i.ModsDir(), name)); err != nil && !os.IsNotExist(err) {
 			return err
 		}
 	}
-
-	for _, f := range files {
-		os.Remove(filepath.Join(i.ModsDir(), f.Name()))
+	for _, pack := range state.Packs {
+		if err := os.Remove(filepath.Join(i.McDir(), filepath.FromSlash(pack))); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	state.Mods = []string{}
+	state.Packs = []string{}
+	i.keptFiles = nil
+	i.conflictFiles = nil
+	extracted := make(map[string]bool)
+	// extracted files that are replaced by the overwrites are not merged or cleaned up
+	overwritten, err := i.overwrittenFiles()
+	if err != nil {
+		return err
 	}
 
 	for _, dep := range i.Lockfile.Dependencies {
+		localFile, err := i.LocalFile(dep)
+		if err != nil {
+			return err
+		}
 		// skip packages with no binary
-		if dep.URL == "" {
+		if dep.URL == "" && localFile == "" {
 			continue
 		}
 		from := filepath.Join(i.PackageCacheDir(), dep.Name, dep.Version+dep.FileExt())
-		to := filepath.Join(i.ModsDir(), dep.Filename())
+		if localFile != "" {
+			from = localFile
+		}
 
 		// extract modpack content and stuff, don't symlink them into the mods folder
 		if dep.Type == manifest.DependencyLockTypeModpack {
-			if err := i.handleModpackDependencyCopy(dep); err != nil {
+			if err := i.handleModpackDependencyCopy(dep, from, state, overwritten); err != nil {
 				return err
 			}
+			extracted[dep.Name] = true
 			continue
 		}
 
-		copyFallback := false
-		// windows required admin permissions for symlinks (yea …)
-		if runtime.GOOS == "windows" {
-			if !copyFallback {
-				err = os.Link(from, to)
-				// first time link fails, we just fallback to copying
-				if err != nil {
-					copyFallback = true
-				}
+		targets, err := i.InstallPaths(dep)
+		if err != nil {
+			return err
+		}
+		for _, target := range targets {
+			to := filepath.Join(i.McDir(), filepath.FromSlash(target))
+			// the player placed a file with the same name. keep it
+			if _, err := os.Lstat(to); err == nil {
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(to), os.ModePerm); err != nil {
+				return err
 			}
-			if copyFallback {
-				err = copyFileContents(from, to)
+			if err := linkFile(from, to); err != nil {
+				return err
+			}
+
+			if dep.Type == manifest.DependencyLockTypeMod {
+				state.Mods = append(state.Mods, path.Base(target))
+			} else {
+				state.Packs = append(state.Packs, target)
 			}
-		} else {
-			err = os.Symlink(from, to)
 		}
-		if err != nil {
+	}
+
+	// clean up the files of removed modpacks
+	for name, previous := range state.Extracted {
+		if extracted[name] {
+			continue
+		}
+		if err := i.cleanupExtracted(previous, nil, overwritten); err != nil {
+			return err
+		}
+		if err := os.RemoveAll(i.pristineDir(name)); err != nil {
 			return err
 		}
+		delete(state.Extracted, name)
+	}
+
+	return state.Save()
+}
+
+// InstallPaths returns the paths (relative to `McDir`) the dependency should be linked to.
+// Data packs are linked into the `Package.Savegame` world or into all worlds if it is not set.
+// Modpacks have no install path, they are extracted
+func (i *Instance) InstallPaths(dep *manifest.DependencyLock) ([]string, error) {
+	switch dep.Type {
+	case manifest.DependencyLockTypeModpack:
+		return nil, nil
+	case manifest.DependencyLockTypeDatapack:
+		return i.datapackPaths(dep)
 	}
+	return []string{dep.InstallPath()}, nil
+}
 
+// datapackPaths returns the paths of a data pack in the `Package.Savegame` world or in all worlds
+func (i *Instance) datapackPaths(dep *manifest.DependencyLock) ([]string, error) {
+
+	worlds := []string{i.Manifest.Package.Savegame}
+	if worlds[0] == "" {
+		saves, err := ioutil.ReadDir(filepath.Join(i.McDir(), "saves"))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		worlds = worlds[:0]
+		for _, save := range saves {
+			if save.IsDir() {
+				worlds = append(worlds, save.Name())
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(worlds))
+	for _, world := range worlds {
+		paths = append(paths, path.Join("saves", world, "datapacks", dep.Name+dep.FileExt()))
+	}
+	return paths, nil
+}
+
+// linkFile symlinks from to to. On windows a hard link is tried instead (symlinks require
+// admin permissions there) and the file is copied if that fails
+func linkFile(from string, to string) error {
+	if runtime.GOOS != "windows" {
+		return os.Symlink(from, to)
+	}
+	if err := os.Link(from, to); err != nil {
+		return copyFileContents(from, to)
+	}
 	return nil
 }
 
-func (i *Instance) handleModpackDependencyCopy(dep *manifest.DependencyLock) error {
+// LocalFile returns the path of a local file that is used instead of the downloaded dependency.
+// This is the built jar of mods linked with `minepkg link` and of mods in the same workspace.
+// Packs and modpacks in the same workspace are packed from source (see `packWorkspaceMember`).
+// An empty string is returned for other dependencies
+func (i *Instance) LocalFile(dep *manifest.DependencyLock) (string, error) {
+	if dep.Type == manifest.DependencyLockTypeMod {
+		linked, err := i.IsLinked(dep.Name)
+		if err != nil {
+			return "", err
+		}
+		if linked {
+			return i.linkedJar(dep.Name)
+		}
+	}
+	if dep.Provider != "workspace" || i.workspace == nil {
+		return "", nil
+	}
+	member := i.workspace.Member(dep.Name)
+	if member == nil {
+		return "", fmt.Errorf("%s is not a member of the workspace anymore", dep.Name)
+	}
+
+	memberInstance := &Instance{Directory: member.Dir, Manifest: member.Manifest}
+	if dep.Type != manifest.DependencyLockTypeMod {
+		return i.packWorkspaceMember(dep, memberInstance)
+	}
+	jars, err := memberInstance.FindModJar()
+	switch {
+	case errors.Is(err, ErrNoBuildFiles):
+		// not built yet. the jar is linked after it was built
+		return "", nil
+	case err != nil:
+		return "", fmt.Errorf("workspace member %s has no built jar: %w", dep.Name, err)
+	}
+	return jars[0].Path(), nil
+}
+
+// packWorkspaceMember zips the source of a pack or modpack workspace member like `minepkg publish` does.
+// Modpacks contain their overwrites, other packs the files in their directory.
+// The zip is saved in the minecraft directory of this instance and replaced on every call
+func (i *Instance) packWorkspaceMember(dep *manifest.DependencyLock, member *Instance) (string, error) {
+	dir := member.Directory
+	files, err := member.PackFiles()
+	if dep.Type == manifest.DependencyLockTypeModpack {
+		dir = member.OverwritesDir()
+		files, err = member.PackageFiles()
+	}
+	switch {
+	case err != nil:
+		return "", fmt.Errorf("workspace member %s: %w", dep.Name, err)
+	case len(files) == 0 && dep.Type != manifest.DependencyLockTypeModpack:
+		return "", fmt.Errorf("workspace member %s contains no files", dep.Name)
+	}
 
-	modpackPath := filepath.Join(i.PackageCacheDir(), dep.Name, dep.Version+".zip")
+	target := filepath.Join(i.McDir(), ".minepkg-workspace", dep.Name+dep.FileExt())
+	if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+		return "", err
+	}
+	f, err := os.Create(target)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if err := pack.WriteZip(f, dir, files); err != nil {
+		return "", fmt.Errorf("workspace member %s: %w", dep.Name, err)
+	}
+	return target, nil
+}
+
+func (i *Instance) handleModpackDependencyCopy(dep *manifest.DependencyLock, modpackPath string, state *State, overwritten map[string]bool) error {
 	pkg, err := pack.Open(modpackPath)
 	if err != nil {
 		return err
 	}
 	defer pkg.Close()
-	return pkg.ExtractModpack(i.McDir())
+
+	// remove files the previous version shipped but this one does not
+	hashes, err := pkg.FileHashes()
+	if err != nil {
+		return err
+	}
+	files := trackedModpackFiles(hashes)
+	previous := state.Extracted[dep.Name]
+	if err := i.cleanupExtracted(previous, files, overwritten); err != nil {
+		return err
+	}
+
+	// keep the changes of the player
+	handled, err := i.updateExtracted(dep.Name, pkg, previous, files, overwritten)
+	if err != nil {
+		return err
+	}
+	if err := pkg.ExtractModpackExcept(i.McDir(), handled); err != nil {
+		return err
+	}
+	if err := i.savePristine(dep.Name, pkg, files); err != nil {
+		return err
+	}
+	state.Extracted[dep.Name] = &ExtractedFiles{Version: dep.Version, Files: files}
+
+	// mods shipped by the modpack are managed like dependencies
+	for _, f := range pkg.Files() {
+		if path.Dir(f.Name) == "mods" && !f.FileInfo().IsDir() {
+			state.Mods = append(state.Mods, path.Base(f.Name))
+		}
+	}
+	return nil
+}
+
+// DependencyDownloader returns a download item for the given dependency. IPFS is tried
+// first (if configured and the dependency has an IPFS hash), the download URL is used otherwise
+func (i *Instance) DependencyDownloader(dep *manifest.DependencyLock) downloadmgr.Downloader {
+	p := filepath.Join(i.PackageCacheDir(), dep.Name, dep.Version+dep.FileExt())
+	item := downloadmgr.NewHTTPItem(dep.URL, p)
+	item.Sha256 = dep.Sha256
+	item.Sha1 = dep.Sha1
+
+	if !ipfs.Default.Enabled() || dep.IPFSHash == "" {
+		return item
+	}
+	downloaders := ipfs.Default.Downloaders(dep.IPFSHash, p, dep.Sha256)
+	return downloadmgr.NewFallbackItem(append(downloaders, item)...)
 }
 
 // EnsureDependencies downloads missing dependencies
@@ -159,8 +380,7 @@ func (i *Instance) EnsureDependencies(ctx context.Context) error {
 
 	mgr := downloadmgr.New()
 	for _, m := range missingFiles {
-		p := filepath.Join(i.PackageCacheDir(), m.Name, m.Version+m.FileExt())
-		mgr.Add(downloadmgr.NewHTTPItem(m.URL, p))
+		mgr.Add(i.DependencyDownloader(m))
 	}
 
 	if err := mgr.Start(ctx); err != nil {
@@ -0,0 +1,232 @@
+package instances
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minepkg/minepkg/internals/workspace"
+	"github.com/minepkg/minepkg/pkg/manifest"
+)
+
+func TestLinkDependenciesPacks(t *testing.T) {
+	deps := []*manifest.DependencyLock{
+		{Name: "test-textures", Version: "1.0.0", Type: manifest.DependencyLockTypeResourcepack},
+		{Name: "test-shaders", Version: "1.0.0", Type: manifest.DependencyLockTypeShaderpack},
+		{Name: "test-data", Version: "1.0.0", Type: manifest.DependencyLockTypeDatapack},
+	}
+	instance := newTestInstance(t, deps...)
+	for _, dep := range deps {
+		dep.URL = "https://example.com/" + dep.Name + ".zip"
+		cached := filepath.Join(instance.PackageCacheDir(), dep.Name, dep.Version+dep.FileExt())
+		os.MkdirAll(filepath.Dir(cached), os.ModePerm)
+		ioutil.WriteFile(cached, []byte("zip"), 0644)
+	}
+	for _, world := range []string{"world-a", "world-b"} {
+		os.MkdirAll(filepath.Join(instance.McDir(), "saves", world), os.ModePerm)
+	}
+
+	if err := instance.LinkDependencies(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"resourcepacks/test-textures.zip",
+		"shaderpacks/test-shaders.zip",
+		"saves/world-a/datapacks/test-data.zip",
+		"saves/world-b/datapacks/test-data.zip",
+	}
+	for _, path := range expected {
+		if _, err := os.Stat(filepath.Join(instance.McDir(), filepath.FromSlash(path))); err != nil {
+			t.Errorf("expected %s to be linked: %v", path, err)
+		}
+	}
+
+	// only the selected savegame gets data packs
+	instance.Manifest.Package.Savegame = "world-a"
+	if err := instance.LinkDependencies(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Lstat(filepath.Join(instance.McDir(), "saves", "world-b", "datapacks", "test-data.zip")); !os.IsNotExist(err) {
+		t.Error("expected data pack to be removed from world-b")
+	}
+}
+
+func writeModpackZip(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	archive := zip.NewWriter(f)
+	for name, content := range files {
+		w, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLinkDependenciesModpackCleanup(t *testing.T) {
+	dep := &manifest.DependencyLock{
+		Name:    "test-pack",
+		Version: "1.0.0",
+		Type:    manifest.DependencyLockTypeModpack,
+		URL:     "https://example.com/test-pack.zip",
+	}
+	instance := newTestInstance(t, dep)
+	writeModpackZip(t, filepath.Join(instance.PackageCacheDir(), "test-pack", "1.0.0.zip"), map[string]string{
+		"config/kept.txt":       "v1",
+		"config/changed.txt":    "v1",
+		"kubejs/scripts/old.js": "v1",
+	})
+	if err := instance.LinkDependencies(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the player changes a file that is dropped in the next version
+	ioutil.WriteFile(filepath.Join(instance.McDir(), "config", "changed.txt"), []byte("mine"), 0644)
+
+	dep.Version = "2.0.0"
+	writeModpackZip(t, filepath.Join(instance.PackageCacheDir(), "test-pack", "2.0.0.zip"), map[string]string{
+		"config/kept.txt": "v2",
+	})
+	if err := instance.LinkDependencies(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(instance.McDir(), "kubejs")); !os.IsNotExist(err) {
+		t.Error("expected kubejs/scripts/old.js and its empty directories to be removed")
+	}
+	if raw, _ := ioutil.ReadFile(filepath.Join(instance.McDir(), "config", "kept.txt")); string(raw) != "v2" {
+		t.Errorf("expected config/kept.txt to be updated, got %q", raw)
+	}
+	if _, err := os.Stat(filepath.Join(instance.McDir(), "config", "changed.txt")); err != nil {
+		t.Error("expected the changed file to be kept")
+	}
+	if kept := instance.KeptFiles(); len(kept) != 1 || kept[0] != "config/changed.txt" {
+		t.Errorf("expected config/changed.txt to be reported as kept, got %v", kept)
+	}
+}
+
+func TestLinkDependenciesModpackMerge(t *testing.T) {
+	dep := &manifest.DependencyLock{
+		Name:    "test-pack",
+		Version: "1.0.0",
+		Type:    manifest.DependencyLockTypeModpack,
+		URL:     "https://example.com/test-pack.zip",
+	}
+	instance := newTestInstance(t, dep)
+	writeModpackZip(t, filepath.Join(instance.PackageCacheDir(), "test-pack", "1.0.0.zip"), map[string]string{
+		"options.txt":      "fov=70\nrenderDistance=8\n",
+		"config/mod.cfg":   "speed=1\n",
+		"config/pack.json": "{\"version\": 1}\n",
+		"config/other.cfg": "color=red\n",
+	})
+	// the overwrites replace a file of the modpack
+	os.MkdirAll(filepath.Join(instance.OverwritesDir(), "config"), os.ModePerm)
+	ioutil.WriteFile(filepath.Join(instance.OverwritesDir(), "config", "other.cfg"), []byte("color=blue\n"), 0644)
+	read := func(path string) string {
+		raw, _ := ioutil.ReadFile(filepath.Join(instance.McDir(), filepath.FromSlash(path)))
+		return string(raw)
+	}
+	write := func(path string, content string) {
+		ioutil.WriteFile(filepath.Join(instance.McDir(), filepath.FromSlash(path)), []byte(content), 0644)
+	}
+
+	if err := instance.LinkDependencies(); err != nil {
+		t.Fatal(err)
+	}
+	if err := instance.CopyOverwrites(); err != nil {
+		t.Fatal(err)
+	}
+
+	// changes of the player survive relinking the same version
+	write("options.txt", "fov=90\nrenderDistance=8\n")
+	write("config/mod.cfg", "speed=5\n")
+	if err := instance.LinkDependencies(); err != nil {
+		t.Fatal(err)
+	}
+	if got := read("options.txt"); got != "fov=90\nrenderDistance=8\n" {
+		t.Fatalf("expected options.txt to be kept, got %q", got)
+	}
+
+	dep.Version = "2.0.0"
+	writeModpackZip(t, filepath.Join(instance.PackageCacheDir(), "test-pack", "2.0.0.zip"), map[string]string{
+		"options.txt":      "fov=70\nrenderDistance=12\n",
+		"config/mod.cfg":   "speed=2\n",
+		"config/pack.json": "{\"version\": 2}\n",
+		"config/other.cfg": "color=green\n",
+	})
+	if err := instance.LinkDependencies(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(instance.McDir(), "config", "other.cfg"+ConflictSuffix)); !os.IsNotExist(err) {
+		t.Error("expected no conflict copy of the overwritten config/other.cfg")
+	}
+
+	if got := read("options.txt"); got != "fov=90\nrenderDistance=12\n" {
+		t.Errorf("expected options.txt to be merged, got %q", got)
+	}
+	if got := read("config/pack.json"); got != "{\"version\": 2}\n" {
+		t.Errorf("expected unchanged config/pack.json to be updated, got %q", got)
+	}
+	if got := read("config/mod.cfg"); got != "speed=5\n" {
+		t.Errorf("expected the conflicting config/mod.cfg of the player to be kept, got %q", got)
+	}
+	if got := read("config/mod.cfg" + ConflictSuffix); got != "speed=2\n" {
+		t.Errorf("expected the new config/mod.cfg to be saved as conflict copy, got %q", got)
+	}
+	if conflicts := instance.ConflictFiles(); len(conflicts) != 1 || conflicts[0] != "config/mod.cfg" {
+		t.Errorf("expected config/mod.cfg to be reported as conflict, got %v", conflicts)
+	}
+}
+
+func TestLinkDependenciesWorkspacePacks(t *testing.T) {
+	texturesDir := t.TempDir()
+	ioutil.WriteFile(filepath.Join(texturesDir, "pack.mcmeta"), []byte("{}"), 0644)
+	packDir := t.TempDir()
+	os.MkdirAll(filepath.Join(packDir, "overwrites", "config"), os.ModePerm)
+	ioutil.WriteFile(filepath.Join(packDir, "overwrites", "config", "pack.txt"), []byte("from source"), 0644)
+
+	member := func(dir string, typ string, name string) *workspace.Member {
+		man := manifest.New()
+		man.Package.Type = typ
+		man.Package.Name = name
+		return &workspace.Member{Dir: dir, Manifest: man}
+	}
+	members := []*workspace.Member{
+		member(texturesDir, manifest.TypeResourcepack, "textures"),
+		member(packDir, manifest.TypeModpack, "pack"),
+	}
+	instance := newTestInstance(t,
+		&manifest.DependencyLock{Name: "textures", Version: "local", Type: manifest.DependencyLockTypeResourcepack, Provider: "workspace"},
+		&manifest.DependencyLock{Name: "pack", Version: "local", Type: manifest.DependencyLockTypeModpack, Provider: "workspace"},
+	)
+	instance.workspace = &workspace.Workspace{Members: members}
+
+	if err := instance.LinkDependencies(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := zip.OpenReader(filepath.Join(instance.McDir(), "resourcepacks", "textures.zip"))
+	if err != nil {
+		t.Fatalf("expected the resource pack member to be linked: %v", err)
+	}
+	defer r.Close()
+	if len(r.File) == 0 || r.File[0].Name != "pack.mcmeta" {
+		t.Errorf("expected the resource pack to contain pack.mcmeta")
+	}
+	if raw, _ := ioutil.ReadFile(filepath.Join(instance.McDir(), "config", "pack.txt")); string(raw) != "from source" {
+		t.Errorf("expected the modpack member to be extracted, got %q", raw)
+	}
+}
@@ -54,7 +54,6 @@ func (i *Instance) BuildMod() *exec.Cmd {
 
 	// TODO: I don't think this is multi platform
 	build := exec.Command("sh", []string{"-c", buildScript}...)
-	build.Env = os.Environ()
 
 	if runtime.GOOS == "windows" {
 		// hack windows compatibility – space after gradlew ensures that this does not have .bat there anyway
@@ -63,6 +62,9 @@ func (i *Instance) BuildMod() *exec.Cmd {
 		}
 		build = exec.Command("powershell", []string{"-Command", buildScript}...)
 	}
+	build.Env = os.Environ()
+	// build in the package directory, even if minepkg was started in a subdirectory
+	build.Dir = i.Directory
 
 	return build
 }
@@ -104,6 +106,10 @@ func (i *Instance) FindModJar() ([]MatchedJar, error) {
 }
 
 func (i *Instance) findModJarCandidatesFromPattern(pattern string) ([]MatchedJar, error) {
+	// relative patterns are relative to the package directory
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(i.Directory, pattern)
+	}
 	matches, err := filepath.Glob(pattern)
 	if err != nil {
 		return nil, err
@@ -133,7 +139,8 @@ func (i *Instance) findModJarCandidatesFromPattern(pattern string) ([]MatchedJar
 }
 
 func (i *Instance) findModJarCandidates() ([]MatchedJar, error) {
-	files, err := ioutil.ReadDir("./build/libs")
+	libsDir := filepath.Join(i.Directory, "build", "libs")
+	files, err := ioutil.ReadDir(libsDir)
 	if err != nil {
 		return nil, ErrNoBuildFiles
 	}
@@ -145,7 +152,7 @@ func (i *Instance) findModJarCandidates() ([]MatchedJar, error) {
 	jars := make([]MatchedJar, len(filtered))
 	for ix, file := range filtered {
 		jars[ix] = MatchedJar{
-			path: filepath.Join("./build/libs", file.Name()),
+			path: filepath.Join(libsDir, file.Name()),
 			stat: file,
 		}
 	}
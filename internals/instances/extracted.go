@@ -0,0 +1,208 @@
+package instances
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/minepkg/minepkg/internals/merge"
+	"github.com/minepkg/minepkg/internals/pack"
+	"github.com/minepkg/minepkg/internals/utils"
+)
+
+// ConflictSuffix is appended to the new version of a modpack file that could not be merged with the changes of the player.
+// The file of the player is kept
+const ConflictSuffix = ".minepkg-new"
+
+// ExtractedFiles are the files that were extracted from a single modpack dependency
+type ExtractedFiles struct {
+	// Version is the extracted version of the modpack
+	Version string `toml:"version"`
+	// Files maps the paths (relative to `McDir`) to the sha256 hash of the extracted content
+	Files map[string]string `toml:"files"`
+}
+
+// KeptFiles returns the files of old modpack versions that were not removed by the last
+// `LinkDependencies`, because they were changed after they were extracted. Paths are relative to `McDir`
+func (i *Instance) KeptFiles() []string {
+	sort.Strings(i.keptFiles)
+	return i.keptFiles
+}
+
+// ConflictFiles returns the files that were changed by the player and by the last modpack update, but could
+// not be merged. The new version is saved next to them with the `ConflictSuffix`. Paths are relative to `McDir`
+func (i *Instance) ConflictFiles() []string {
+	sort.Strings(i.conflictFiles)
+	return i.conflictFiles
+}
+
+// pristineDir is the directory with the unchanged text files of the last extracted version of a modpack dependency.
+// They are the base of the three-way merge when the modpack is updated
+func (i *Instance) pristineDir(name string) string {
+	return filepath.Join(i.McDir(), ".minepkg-pristine", name)
+}
+
+// updateExtracted handles the files of the modpack that already exist. Files the player did not change are replaced.
+// Files the modpack did not change are kept. If both changed, text files are merged with the pristine copy of the
+// previous version; if that fails, the file of the player is kept and the new version is saved as a conflict copy.
+// Files in overwritten are always replaced, the overwrites are copied over them again.
+// The returned files are handled and should not be extracted
+func (i *Instance) updateExtracted(name string, pkg *pack.PackageFile, previous *ExtractedFiles, files map[string]string, overwritten map[string]bool) (map[string]bool, error) {
+	handled := make(map[string]bool)
+	for path, hash := range files {
+		if overwritten[path] {
+			continue
+		}
+		fullPath := filepath.Join(i.McDir(), filepath.FromSlash(path))
+		current, err := utils.Sha256File(fullPath)
+		switch {
+		case os.IsNotExist(err):
+			continue
+		case err != nil:
+			return nil, err
+		case current == hash:
+			// already up to date
+			handled[path] = true
+			continue
+		case previous == nil:
+			// we do not know what was extracted before. the modpack wins
+			continue
+		}
+
+		previousHash, known := previous.Files[path]
+		switch {
+		case known && current == previousHash:
+			// not changed by the player
+			continue
+		case known && hash == previousHash:
+			// not changed by the modpack
+			handled[path] = true
+			continue
+		}
+
+		// changed on both sides
+		handled[path] = true
+		theirs, err := pkg.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if merged, ok := i.mergeExtracted(name, path, fullPath, theirs); ok && known {
+			if err := ioutil.WriteFile(fullPath, merged, 0644); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := ioutil.WriteFile(fullPath+ConflictSuffix, theirs, 0644); err != nil {
+			return nil, err
+		}
+		i.conflictFiles = append(i.conflictFiles, path)
+	}
+	return handled, nil
+}
+
+// mergeExtracted merges the changes of the player in fullPath with the new version (theirs)
+func (i *Instance) mergeExtracted(name string, path string, fullPath string, theirs []byte) ([]byte, bool) {
+	if !merge.Mergeable(path) {
+		return nil, false
+	}
+	base, err := ioutil.ReadFile(filepath.Join(i.pristineDir(name), filepath.FromSlash(path)))
+	if err != nil {
+		return nil, false
+	}
+	ours, err := ioutil.ReadFile(fullPath)
+	if err != nil {
+		return nil, false
+	}
+	return merge.ThreeWay(base, ours, theirs)
+}
+
+// savePristine replaces the pristine copies of a modpack dependency with the text files of pkg
+func (i *Instance) savePristine(name string, pkg *pack.PackageFile, files map[string]string) error {
+	dir := i.pristineDir(name)
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	for path := range files {
+		if !merge.Mergeable(path) {
+			continue
+		}
+		content, err := pkg.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(target, content, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// overwrittenFiles returns the `OverwriteFiles` as a set of slash separated paths
+func (i *Instance) overwrittenFiles() (map[string]bool, error) {
+	files, err := i.OverwriteFiles()
+	if err != nil {
+		return nil, err
+	}
+	overwritten := make(map[string]bool, len(files))
+	for _, file := range files {
+		overwritten[filepath.ToSlash(file)] = true
+	}
+	return overwritten, nil
+}
+
+// trackedModpackFiles returns the hashes of files that are tracked for cleanup.
+// Saves are not tracked, they belong to the player once they were extracted
+func trackedModpackFiles(hashes map[string]string) map[string]string {
+	files := make(map[string]string, len(hashes))
+	for path, hash := range hashes {
+		if !strings.HasPrefix(path, "saves/") {
+			files[path] = hash
+		}
+	}
+	return files
+}
+
+// cleanupExtracted removes the files of a previously extracted modpack version that are not part of files anymore.
+// Files that were changed since they were extracted are kept (see `KeptFiles`). Files in overwritten belong to the overwrites
+func (i *Instance) cleanupExtracted(previous *ExtractedFiles, files map[string]string, overwritten map[string]bool) error {
+	if previous == nil {
+		return nil
+	}
+	for path, hash := range previous.Files {
+		if _, ok := files[path]; ok || overwritten[path] {
+			continue
+		}
+		fullPath := filepath.Join(i.McDir(), filepath.FromSlash(path))
+		current, err := utils.Sha256File(fullPath)
+		switch {
+		case os.IsNotExist(err):
+			continue
+		case err != nil:
+			return err
+		case current != hash:
+			i.keptFiles = append(i.keptFiles, path)
+			continue
+		}
+		if err := os.Remove(fullPath); err != nil {
+			return err
+		}
+		i.removeEmptyDirs(filepath.Dir(fullPath))
+	}
+	return nil
+}
+
+// removeEmptyDirs removes dir and its parents up to `McDir` as long as they are empty
+func (i *Instance) removeEmptyDirs(dir string) {
+	for dir != i.McDir() && strings.HasPrefix(dir, i.McDir()) {
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+
+	"github.com/minepkg/minepkg/internals/mirrors"
 )
 
 type fabricLoaderVersion struct {
@@ -62,12 +64,12 @@ func getFabricMappingVersions(ctx context.Context) ([]fabricMappingVersion, erro
 }
 
 func fabricGet(ctx context.Context, url string) (*http.Response, error) {
-	req, _ := http.NewRequest("GET", url, nil)
-	req.Header.Set("User-Agent", "minepkg (https://github.com/minepkg/minepkg)")
-	req.Header.Set("Content-Type", "application/json")
-	res, err := http.DefaultClient.Do(req.WithContext(ctx))
-	if err == nil && res.StatusCode != 200 {
-		return res, fmt.Errorf("fabric meta API did respond with unexpected status %s", res.Status)
+	res, err := mirrors.Default.Do(ctx, http.DefaultClient, url, func(req *http.Request) {
+		req.Header.Set("User-Agent", "minepkg (https://github.com/minepkg/minepkg)")
+		req.Header.Set("Content-Type", "application/json")
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fabric meta API request failed: %w", err)
 	}
-	return res, err
+	return res, nil
 }
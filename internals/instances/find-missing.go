@@ -1,6 +1,7 @@
 package instances
 
 import (
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
@@ -8,6 +9,7 @@ import (
 	"path/filepath"
 
 	"github.com/minepkg/minepkg/internals/minecraft"
+	"github.com/minepkg/minepkg/internals/mirrors"
 )
 
 // FindMissingLibraries returns all missing assets
@@ -36,7 +38,7 @@ func (i *Instance) FindMissingAssets(man *minecraft.LaunchManifest) ([]minecraft
 	assetJSONPath := filepath.Join(i.AssetsDir(), "indexes", man.Assets+".json")
 	buf, err := ioutil.ReadFile(assetJSONPath)
 	if err != nil {
-		res, err := http.Get(man.AssetIndex.URL)
+		res, err := mirrors.Default.Get(context.TODO(), http.DefaultClient, man.AssetIndex.URL)
 		if err != nil {
 			return nil, err
 		}
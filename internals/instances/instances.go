@@ -12,6 +12,7 @@ import (
 
 	"github.com/minepkg/minepkg/internals/api"
 	"github.com/minepkg/minepkg/internals/mojang"
+	"github.com/minepkg/minepkg/internals/workspace"
 	"github.com/minepkg/minepkg/pkg/manifest"
 	"github.com/pelletier/go-toml"
 )
@@ -56,7 +57,14 @@ type Instance struct {
 	Lockfile          *manifest.Lockfile
 	MojangCredentials *mojang.AuthResponse
 	MinepkgAPI        *api.MinepkgAPI
-
+	// ServerAddress is the address of the server this instance joins (if any).
+	// It is available in templated overwrites
+	ServerAddress string
+
+	workspace                    *workspace.Workspace
+	keptFiles                    []string
+	conflictFiles                []string
+	links                        map[string]bool
 	isFromWd                     bool
 	launchCmd                    string
 	lockfileNeedsRenameMigration bool
@@ -120,11 +128,20 @@ func (i *Instance) ManifestPath() string {
 	return filepath.Join(i.Directory, "minepkg.toml")
 }
 
-// LockfilePath is the path to the `.minepkg-lock.toml`. The file does not necessarily exist
+// LockfilePath is the path to the `.minepkg-lock.toml`. The file does not necessarily exist.
+// Workspace members share the lockfile in the workspace root
 func (i *Instance) LockfilePath() string {
+	if i.workspace != nil {
+		return i.workspace.LockfilePath()
+	}
 	return filepath.Join(i.Directory, ".minepkg-lock.toml")
 }
 
+// Workspace returns the workspace this instance is a member of or nil
+func (i *Instance) Workspace() *workspace.Workspace {
+	return i.workspace
+}
+
 // legacyLockfilePath is the old path `minepkg-lock.toml` (no dot)
 func (i *Instance) legacyLockfilePath() string {
 	return filepath.Join(i.Directory, "minepkg-lock.toml")
@@ -175,13 +192,8 @@ func New() *Instance {
 
 // NewFromDir tries to detect a instance in the given directory
 func NewFromDir(dir string) (*Instance, error) {
-	manifestToml, err := ioutil.ReadFile("./minepkg.toml")
+	manifest, err := manifestFromDir(dir)
 	if err != nil {
-		// TODO only for not found errors
-		return nil, ErrNoInstance
-	}
-	manifest := manifest.Manifest{}
-	if err = toml.Unmarshal(manifestToml, &manifest); err != nil {
 		return nil, err
 	}
 
@@ -199,13 +211,21 @@ func NewFromDir(dir string) (*Instance, error) {
 	}
 
 	instance := &Instance{
-		Manifest:  &manifest,
+		Manifest:  manifest,
 		Directory: dir,
 		GlobalDir: filepath.Join(userConfig, "minepkg"),
 		CacheDir:  filepath.Join(userCache, "minepkg"),
 		isFromWd:  true,
 	}
 
+	ws, err := workspace.Find(dir)
+	if err != nil {
+		return nil, err
+	}
+	if ws != nil && ws.Member(manifest.Package.Name) != nil {
+		instance.workspace = ws
+	}
+
 	// initialize lockfile
 	if err := instance.initLockfile(); err != nil {
 		return nil, err
@@ -220,15 +240,53 @@ func NewFromDir(dir string) (*Instance, error) {
 }
 
 // NewFromWd tries to detect a instance in the current working directory
+// or the nearest parent directory containing a minepkg.toml
 func NewFromWd() (*Instance, error) {
-	dir, err := os.Getwd()
+	wd, err := os.Getwd()
 	if err != nil {
 		return nil, err
 	}
 
+	dir, err := FindDir(wd)
+	if err != nil {
+		return nil, err
+	}
 	return NewFromDir(dir)
 }
 
+// FindDir returns dir or the nearest parent directory that contains a minepkg.toml.
+// `ErrNoInstance` is returned if there is none
+func FindDir(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "minepkg.toml")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ErrNoInstance
+		}
+		dir = parent
+	}
+}
+
+// manifestFromDir reads the minepkg.toml in dir
+func manifestFromDir(dir string) (*manifest.Manifest, error) {
+	manifestToml, err := ioutil.ReadFile(filepath.Join(dir, "minepkg.toml"))
+	if err != nil {
+		// TODO only for not found errors
+		return nil, ErrNoInstance
+	}
+	man := &manifest.Manifest{}
+	if err = toml.Unmarshal(manifestToml, man); err != nil {
+		return nil, err
+	}
+	return man, nil
+}
+
 // initLockfile sets the lockfile or creates one
 func (i *Instance) initLockfile() error {
 	lockfile, err := LockfileFromPath(i.LockfilePath())
@@ -239,6 +297,9 @@ func (i *Instance) initLockfile() error {
 		}
 
 		// non existing lockfile is not bad
+		if i.workspace != nil {
+			return nil
+		}
 
 		// try old name
 		if lockfile, err = LockfileFromPath(i.legacyLockfilePath()); err != nil {
@@ -251,6 +312,10 @@ func (i *Instance) initLockfile() error {
 		i.lockfileNeedsRenameMigration = true
 	}
 
+	if i.workspace != nil {
+		lockfile = lockfile.MemberLockfile(i.Manifest.Package.Name)
+	}
+
 	i.Lockfile = lockfile
 	return nil
 }
@@ -261,8 +326,20 @@ func (i *Instance) SaveManifest() error {
 	return ioutil.WriteFile(i.ManifestPath(), manifest.Bytes(), 0644)
 }
 
-// SaveLockfile saves the lockfile to the current directory
+// SaveLockfile saves the lockfile to the current directory.
+// Workspace members update their part of the shared workspace lockfile
 func (i *Instance) SaveLockfile() error {
-	lockfile := i.Lockfile.Buffer()
-	return ioutil.WriteFile(i.LockfilePath(), lockfile.Bytes(), 0644)
+	lockfile := i.Lockfile
+	if i.workspace != nil {
+		shared, err := LockfileFromPath(i.LockfilePath())
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return err
+			}
+			shared = manifest.NewLockfile()
+		}
+		shared.SetMember(i.Manifest.Package.Name, i.Lockfile)
+		lockfile = shared
+	}
+	return ioutil.WriteFile(i.LockfilePath(), lockfile.Buffer().Bytes(), 0644)
 }
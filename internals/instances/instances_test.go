@@ -0,0 +1,28 @@
+package instances
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindDir(t *testing.T) {
+	root := t.TempDir()
+	ioutil.WriteFile(filepath.Join(root, "minepkg.toml"), []byte{}, 0644)
+	sub := filepath.Join(root, "src", "main")
+	os.MkdirAll(sub, os.ModePerm)
+
+	dir, err := FindDir(sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the temp dir might be a symlink (eg. on macOS)
+	if want, _ := filepath.EvalSymlinks(root); dir != root && dir != want {
+		t.Fatalf("expected %s, got %s", root, dir)
+	}
+
+	if _, err := FindDir(t.TempDir()); err != ErrNoInstance {
+		t.Fatalf("expected ErrNoInstance, got %v", err)
+	}
+}
@@ -19,7 +19,9 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/minepkg/minepkg/internals/downloadmgr"
 	"github.com/minepkg/minepkg/internals/minecraft"
+	"github.com/minepkg/minepkg/internals/mirrors"
 	"github.com/minepkg/minepkg/internals/mojang"
 	"github.com/minepkg/minepkg/pkg/manifest"
 	"github.com/pbnjay/memory"
@@ -55,6 +57,21 @@ func (i *Instance) GetLaunchManifest() (*minecraft.LaunchManifest, error) {
 	return man, nil
 }
 
+// JvmArgsEnv is the environment variable that replaces the `launch.jvmArgs` of the manifest
+const JvmArgsEnv = "MINEPKG_JVM_ARGS"
+
+// defaultJvmArgs are used unless `launch.replaceDefaultJvmArgs` is set
+var defaultJvmArgs = []string{
+	"-Xss128M",
+	"-XX:+UnlockExperimentalVMOptions",
+	"-XX:+UseG1GC",
+	"-XX:G1NewSizePercent=20",
+	"-XX:G1ReservePercent=20",
+	"-XX:MaxGCPauseMillis=50",
+	"-XX:G1HeapRegionSize=32M",
+	"-XX:ErrorFile=./jvm-error.log",
+}
+
 // LaunchOptions are options for launching
 type LaunchOptions struct {
 	LaunchManifest *minecraft.LaunchManifest
@@ -72,6 +89,11 @@ type LaunchOptions struct {
 	// RamMiB can be set to the amount of ram in MiB to start Minecraft with
 	// 0 determins the amount by modcount + available system ram
 	RamMiB int
+	// JvmArgs are additional arguments passed to the JVM
+	JvmArgs []string
+	// Stdin and Stdout replace the terminal of a server (optional)
+	Stdin  io.Reader
+	Stdout io.Writer
 }
 
 // Launch will launch the minecraft instance
@@ -211,27 +233,27 @@ func (i *Instance) BuildLaunchCmd(opts *LaunchOptions) (*exec.Cmd, error) {
 	}
 
 	cmdArgs := []string{
-		"-Xss128M",
 		"-Djava.library.path=" + tmpDir,
 		"-Dminecraft.launcher.brand=minepkg",
 		// "-Dminecraft.launcher.version=" + "0.0.2", // TODO: implement!
 		"-Dminecraft.client.jar=" + mcJar,
 		"-cp",
 		strings.Join(cpArgs, javaCpSeperator),
-		fmt.Sprintf("-Xmx%dM", maxRamMiB),
-		"-XX:+UnlockExperimentalVMOptions",
-		"-XX:+UseG1GC",
-		"-XX:G1NewSizePercent=20",
-		"-XX:G1ReservePercent=20",
-		"-XX:MaxGCPauseMillis=50",
-		"-XX:G1HeapRegionSize=32M",
-		"-XX:ErrorFile=./jvm-error.log",
-		launchManifest.MainClass,
 	}
 
-	if opts.RamMiB != 0 {
-		cmdArgs = append([]string{fmt.Sprintf("-Xms%dM", opts.RamMiB)}, cmdArgs...)
+	customJvmArgs := append(i.customJvmArgs(), opts.JvmArgs...)
+	if !i.Manifest.Launch.ReplaceDefaultJvmArgs {
+		cmdArgs = append(cmdArgs, defaultJvmArgs...)
+	}
+	// custom heap settings replace ours
+	if !hasArgPrefix(customJvmArgs, "-Xmx") {
+		cmdArgs = append(cmdArgs, fmt.Sprintf("-Xmx%dM", maxRamMiB))
 	}
+	if opts.RamMiB != 0 && !hasArgPrefix(customJvmArgs, "-Xms") {
+		cmdArgs = append(cmdArgs, fmt.Sprintf("-Xms%dM", opts.RamMiB))
+	}
+	cmdArgs = append(cmdArgs, customJvmArgs...)
+	cmdArgs = append(cmdArgs, launchManifest.MainClass)
 
 	// HACK: prepend this so macos does not crash
 	if runtime.GOOS == "darwin" {
@@ -244,6 +266,7 @@ func (i *Instance) BuildLaunchCmd(opts *LaunchOptions) (*exec.Cmd, error) {
 		// maybe don't use client args for server …
 		cmdArgs = append(cmdArgs, "nogui")
 	}
+	cmdArgs = append(cmdArgs, i.Manifest.Launch.GameArgs...)
 
 	if opts.Debug {
 		fmt.Println("cmd: ")
@@ -269,10 +292,13 @@ func (i *Instance) BuildLaunchCmd(opts *LaunchOptions) (*exec.Cmd, error) {
 
 	if opts.Server {
 		cmd.Stdin = os.Stdin
+		if opts.Stdin != nil {
+			cmd.Stdin = opts.Stdin
+		}
 	}
 
 	// we catch ctrl-c to handle this by ourself
-	c := make(chan os.Signal)
+	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 	go func() {
 		<-c
@@ -281,6 +307,9 @@ func (i *Instance) BuildLaunchCmd(opts *LaunchOptions) (*exec.Cmd, error) {
 	}()
 
 	cmd.Stdout = os.Stdout
+	if opts.Server && opts.Stdout != nil {
+		cmd.Stdout = opts.Stdout
+	}
 	cmd.Stderr = os.Stderr
 
 	// Set the process directory to our minecraft dir
@@ -291,6 +320,25 @@ func (i *Instance) BuildLaunchCmd(opts *LaunchOptions) (*exec.Cmd, error) {
 	return cmd, nil
 }
 
+// customJvmArgs returns the `launch.jvmArgs` of the manifest or the
+// arguments in `MINEPKG_JVM_ARGS` if that is set
+func (i *Instance) customJvmArgs() []string {
+	if env, ok := os.LookupEnv(JvmArgsEnv); ok {
+		return strings.Fields(env)
+	}
+	return append([]string{}, i.Manifest.Launch.JvmArgs...)
+}
+
+// hasArgPrefix returns true if any of the args starts with prefix
+func hasArgPrefix(args []string, prefix string) bool {
+	for _, arg := range args {
+		if strings.HasPrefix(arg, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 func (i *Instance) gameArgs(launchManifest *minecraft.LaunchManifest, opts *LaunchOptions) ([]string, error) {
 	gameArgs := map[string]string{
 		// the minecraft version
@@ -389,7 +437,11 @@ func (i *Instance) fetchFabricManifest(lock *manifest.FabricLock) (*minecraft.La
 		return &manifest, nil
 	}
 
-	res, err := http.Get("https://fabricmc.net/download/vanilla?format=profileJson&loader=" + url.QueryEscape(loader) + "&yarn=" + url.QueryEscape(mappings))
+	res, err := mirrors.Default.Get(
+		context.TODO(),
+		http.DefaultClient,
+		"https://fabricmc.net/download/vanilla?format=profileJson&loader="+url.QueryEscape(loader)+"&yarn="+url.QueryEscape(mappings),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -427,7 +479,7 @@ func (i *Instance) fetchVanillaManifest(version string) (*minecraft.LaunchManife
 	}
 
 	manifest := minecraft.LaunchManifest{}
-	res, err := http.Get(manifestURL)
+	res, err := mirrors.Default.Get(context.TODO(), http.DefaultClient, manifestURL)
 	if err != nil {
 		return nil, err
 	}
@@ -449,7 +501,7 @@ func (i *Instance) fetchVanillaManifest(version string) (*minecraft.LaunchManife
 	}
 
 	// TODO: this is a side effect. it should not be here
-	jarRes, err := http.Get(manifest.Downloads.Client.URL)
+	jarRes, err := mirrors.Default.Get(context.TODO(), http.DefaultClient, manifest.Downloads.Client.URL)
 	if err != nil {
 		return nil, err
 	}
@@ -463,6 +515,11 @@ func (i *Instance) fetchVanillaManifest(version string) (*minecraft.LaunchManife
 	if _, err = io.Copy(jarDest, jarRes.Body); err != nil {
 		return nil, err
 	}
+	if manifest.Downloads.Client.Sha1 != "" {
+		if err := downloadmgr.CheckSha1(manifest.Downloads.Client.Sha1, jarDest.Name()); err != nil {
+			return nil, err
+		}
+	}
 
 	return &manifest, nil
 }
@@ -0,0 +1,31 @@
+package instances
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/minepkg/minepkg/pkg/manifest"
+)
+
+func TestCustomJvmArgs(t *testing.T) {
+	instance := &Instance{Manifest: manifest.New()}
+	instance.Manifest.Launch.JvmArgs = []string{"-Xmx6G", "-Dfml.readTimeout=180"}
+
+	// t.Setenv restores the variable after the test, even if it is unset here
+	t.Setenv(JvmArgsEnv, "")
+	os.Unsetenv(JvmArgsEnv)
+	args := instance.customJvmArgs()
+	if !reflect.DeepEqual(args, instance.Manifest.Launch.JvmArgs) {
+		t.Fatalf("expected manifest args, got %v", args)
+	}
+	if !hasArgPrefix(args, "-Xmx") || hasArgPrefix(args, "-Xms") {
+		t.Fatal("hasArgPrefix did not detect the heap settings correctly")
+	}
+
+	t.Setenv(JvmArgsEnv, " -XX:+UseZGC  -Xmx8G ")
+	args = instance.customJvmArgs()
+	if !reflect.DeepEqual(args, []string{"-XX:+UseZGC", "-Xmx8G"}) {
+		t.Fatalf("expected env args, got %v", args)
+	}
+}
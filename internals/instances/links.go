@@ -0,0 +1,152 @@
+package instances
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pelletier/go-toml"
+)
+
+// LinksFile is the file in the instance directory that lists the linked mods
+const LinksFile = ".minepkg-links.toml"
+
+// Links keeps track of mods that are registered with `minepkg link` for local development.
+// It is saved as `links.toml` in the global directory
+type Links struct {
+	path string
+	// Mods maps package names to their absolute directory
+	Mods map[string]string `toml:"mods"`
+}
+
+// OpenLinks reads the global links from the given global directory.
+// A missing file results in no links
+func OpenLinks(globalDir string) (*Links, error) {
+	links := &Links{
+		path: filepath.Join(globalDir, "links.toml"),
+		Mods: make(map[string]string),
+	}
+
+	raw, err := ioutil.ReadFile(links.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return links, nil
+		}
+		return nil, err
+	}
+	if err := toml.Unmarshal(raw, links); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", links.path, err)
+	}
+	if links.Mods == nil {
+		links.Mods = make(map[string]string)
+	}
+	return links, nil
+}
+
+// Save writes the links to disk
+func (l *Links) Save() error {
+	if err := os.MkdirAll(filepath.Dir(l.path), os.ModePerm); err != nil {
+		return err
+	}
+	buf, err := toml.Marshal(l)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(l.path, buf, 0644)
+}
+
+// instanceLinks is the content of the `LinksFile` of an instance
+type instanceLinks struct {
+	Mods []string `toml:"mods"`
+}
+
+// LinksPath returns the path of the file listing the mods linked into this instance
+func (i *Instance) LinksPath() string {
+	return filepath.Join(i.Directory, LinksFile)
+}
+
+// LinkedMods returns the names of the mods that are linked into this instance
+func (i *Instance) LinkedMods() ([]string, error) {
+	raw, err := ioutil.ReadFile(i.LinksPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+	links := instanceLinks{}
+	if err := toml.Unmarshal(raw, &links); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", LinksFile, err)
+	}
+	return links.Mods, nil
+}
+
+// SetLinkedMods saves the names of the mods that are linked into this instance.
+// The file is removed if no mods are linked
+func (i *Instance) SetLinkedMods(names []string) error {
+	i.links = nil
+	if len(names) == 0 {
+		err := os.Remove(i.LinksPath())
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	sort.Strings(names)
+	buf, err := toml.Marshal(instanceLinks{Mods: names})
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(i.LinksPath(), buf, 0644)
+}
+
+// Links returns the set of mods that are linked into this instance.
+// The `LinksFile` is only read once, later calls return the same set
+func (i *Instance) Links() (map[string]bool, error) {
+	if i.links != nil {
+		return i.links, nil
+	}
+	names, err := i.LinkedMods()
+	if err != nil {
+		return nil, err
+	}
+	i.links = make(map[string]bool, len(names))
+	for _, name := range names {
+		i.links[name] = true
+	}
+	return i.links, nil
+}
+
+// IsLinked returns true if the mod is linked into this instance
+func (i *Instance) IsLinked(name string) (bool, error) {
+	links, err := i.Links()
+	if err != nil {
+		return false, err
+	}
+	return links[name], nil
+}
+
+// linkedJar returns the built jar of a linked mod
+func (i *Instance) linkedJar(name string) (string, error) {
+	links, err := OpenLinks(i.GlobalDir)
+	if err != nil {
+		return "", err
+	}
+	dir, ok := links.Mods[name]
+	if !ok {
+		return "", fmt.Errorf("%s is linked but not registered anymore. Run \"minepkg link\" in its directory", name)
+	}
+
+	man, err := manifestFromDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("linked mod %s: %w", name, err)
+	}
+	linked := &Instance{Directory: dir, Manifest: man}
+	jars, err := linked.FindModJar()
+	if err != nil {
+		return "", fmt.Errorf("linked mod %s has no built jar: %w", name, err)
+	}
+	return jars[0].Path(), nil
+}
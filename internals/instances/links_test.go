@@ -0,0 +1,80 @@
+package instances
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minepkg/minepkg/pkg/manifest"
+)
+
+func TestLocalFileLinked(t *testing.T) {
+	globalDir := t.TempDir()
+
+	// a mod with a build output
+	modDir := t.TempDir()
+	ioutil.WriteFile(filepath.Join(modDir, "minepkg.toml"), []byte(`
+[package]
+type = "mod"
+name = "test-mod"
+
+[requirements]
+minecraft = "1.17.1"
+`), 0644)
+	libsDir := filepath.Join(modDir, "build", "libs")
+	os.MkdirAll(libsDir, os.ModePerm)
+	ioutil.WriteFile(filepath.Join(libsDir, "test-mod-1.0.0.jar"), []byte("jar"), 0644)
+
+	links, err := OpenLinks(globalDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	links.Mods["test-mod"] = modDir
+	if err := links.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	instance := &Instance{
+		GlobalDir: globalDir,
+		Directory: t.TempDir(),
+		Manifest:  manifest.New(),
+	}
+	dep := &manifest.DependencyLock{Name: "test-mod", Version: "1.0.0", Type: manifest.DependencyLockTypeMod}
+
+	jar, err := instance.LocalFile(dep)
+	if err != nil || jar != "" {
+		t.Fatalf("expected no local jar before linking, got %q (%v)", jar, err)
+	}
+
+	if err := instance.SetLinkedMods([]string{"test-mod"}); err != nil {
+		t.Fatal(err)
+	}
+	if linked, err := instance.IsLinked("test-mod"); err != nil || !linked {
+		t.Fatalf("expected test-mod to be linked (%v)", err)
+	}
+	jar, err = instance.LocalFile(dep)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(libsDir, "test-mod-1.0.0.jar"); jar != want {
+		t.Fatalf("expected %s, got %s", want, jar)
+	}
+
+	// removing the last link removes the file
+	if err := instance.SetLinkedMods(nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(instance.LinksPath()); !os.IsNotExist(err) {
+		t.Fatalf("expected %s to be removed", LinksFile)
+	}
+}
+
+func TestIsLinkedInvalidFile(t *testing.T) {
+	instance := &Instance{Directory: t.TempDir(), Manifest: manifest.New()}
+	ioutil.WriteFile(instance.LinksPath(), []byte("mods = ["), 0644)
+
+	if _, err := instance.IsLinked("test-mod"); err == nil {
+		t.Fatalf("expected an error for an invalid %s", LinksFile)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+
+	"github.com/minepkg/minepkg/internals/mirrors"
 )
 
 const mcVersionsURL string = "https://launchermeta.mojang.com/mc/game/version_manifest.json"
@@ -40,12 +42,11 @@ type MinecraftReleaseResponse struct {
 
 // GetMinecraftReleases returns all available Minecraft releases
 func GetMinecraftReleases(ctx context.Context) (*MinecraftReleaseResponse, error) {
-	req, _ := http.NewRequest("GET", mcVersionsURL, nil)
-
-	res, err := http.DefaultClient.Do(req.WithContext(ctx))
+	res, err := mirrors.Default.Get(ctx, http.DefaultClient, mcVersionsURL)
 	if err != nil {
 		return nil, err
 	}
+	defer res.Body.Close()
 	buf, err := ioutil.ReadAll(res.Body)
 	if err != nil {
 		return nil, err
@@ -1,19 +1,71 @@
 package instances
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/minepkg/minepkg/internals/configpatch"
+	"github.com/minepkg/minepkg/internals/ignore"
 )
 
-// CopyOverwrites copies everything from the instance dir (with a few exceptions) to the minecraft dir
-// exceptions are: the minecraft folder itself and minepkg related files (manifest & lockfile)
-func (i *Instance) CopyOverwrites() error {
-	// TODO: error ignored in Walk? check
-	err := filepath.Walk(i.OverwritesDir(), func(fullPath string, info os.FileInfo, _ error) error {
+// defaultIgnore are the ignore patterns that always apply to the overwrites. Patterns in the
+// `.minepkgignore` can add more
+var defaultIgnore = []string{
+	// everything starting with "excluded"
+	"/excluded*",
+	// the minecraft directory
+	"/minecraft/",
+	// hidden dirs or files like ".git"
+	"/.*",
+	// minepkg related files
+	"/minepkg.toml",
+	"/minepkg-lock.toml",
+	"/[Rr][Ee][Aa][Dd][Mm][Ee]*",
+}
+
+// savesIgnore skips the saves dir. it is handled using `Instance.CopyLocalSaves`
+var savesIgnore = []string{"/saves/"}
+
+// packageSavesIgnore skips player related data in saves when packaging a modpack
+var packageSavesIgnore = []string{
+	"/saves/*/advancements*",
+	"/saves/*/playerdata*",
+	"/saves/*/stats*",
+	"/saves/*/session.lock",
+}
+
+// IgnorePath is the path to the `.minepkgignore`. Its patterns are relative to `OverwritesDir`
+// (or to `Directory` for resource, shader and data packs). The file does not necessarily exist
+func (i *Instance) IgnorePath() string {
+	return filepath.Join(i.Directory, ignore.Filename)
+}
+
+// ignoreMatcher returns a matcher with the default patterns, extra and the `.minepkgignore` patterns
+func (i *Instance) ignoreMatcher(extra ...string) (*ignore.Matcher, error) {
+	return ignore.Load(i.IgnorePath(), append(defaultIgnore, extra...)...)
+}
+
+// walkOverwrites calls fn for every file and directory in the overwrites that is not ignored by matcher.
+// path is relative to `OverwritesDir`
+func (i *Instance) walkOverwrites(matcher *ignore.Matcher, fn func(path string, fullPath string, info os.FileInfo) error) error {
+	return walkDir(i.OverwritesDir(), matcher, fn)
+}
+
+// walkDir calls fn for every file and directory in root that is not ignored by matcher.
+// path is relative to root
+func walkDir(root string, matcher *ignore.Matcher, fn func(path string, fullPath string, info os.FileInfo) error) error {
+	return filepath.Walk(root, func(fullPath string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
 		// get a relative path
-		path, err := filepath.Rel(i.OverwritesDir(), fullPath)
+		path, err := filepath.Rel(root, fullPath)
 		if err != nil {
 			return err
 		}
@@ -22,27 +74,32 @@ func (i *Instance) CopyOverwrites() error {
 		case path == ".":
 			// skip root
 			return nil
-		case strings.HasPrefix(path, "excluded"):
-			// skip everything starting with "excluded"
-			return filepath.SkipDir
-		case path == "minecraft" || path == "saves":
-			// skip the minecraft directory
-			// and skip the saves dir. it is handled using `Instance.CopyLocalSaves`
+		case matcher.Match(path, info.IsDir()) && info.IsDir():
 			return filepath.SkipDir
-		case info.IsDir() && strings.HasPrefix(path, "."):
-			// skip any hidden dirs or files like ".git"
-			return filepath.SkipDir
-
-		case path == "minepkg.toml" || path == "minepkg-lock.toml" || strings.HasPrefix(strings.ToLower(path), "readme"):
-			// do not skip the root directory, but skip those files
+		case matcher.Match(path, info.IsDir()):
 			return nil
 		}
+		return fn(path, fullPath, info)
+	})
+}
 
+// CopyOverwrites copies everything from the instance dir to the minecraft dir. Paths matched by the
+// default ignore patterns (eg. the minecraft folder itself and minepkg related files) or the `.minepkgignore` are skipped.
+// Patch files (see `configpatch`) are merged into the existing files after everything was copied.
+// Templates (files ending with `TemplateSuffix`) are rendered with `TemplateData`
+func (i *Instance) CopyOverwrites() error {
+	matcher, err := i.ignoreMatcher(savesIgnore...)
+	if err != nil {
+		return err
+	}
+	patches := make([]string, 0)
+	data := i.TemplateData()
+	err = i.walkOverwrites(matcher, func(path string, fullPath string, info os.FileInfo) error {
 		destPath := filepath.Join(i.McDir(), path)
 
 		// create directory
 		if info.IsDir() {
-			err = os.Mkdir(destPath, os.ModePerm)
+			err := os.Mkdir(destPath, os.ModePerm)
 			if err != nil && os.IsExist(err) {
 				// existing dirs are fine
 				return nil
@@ -51,6 +108,19 @@ func (i *Instance) CopyOverwrites() error {
 			return err
 		}
 
+		// patches are applied later, after the patched files were copied
+		if _, ok := configpatch.Target(path); ok {
+			patches = append(patches, path)
+			return nil
+		}
+
+		if strings.HasSuffix(path, TemplateSuffix) {
+			if err := renderTemplate(fullPath, strings.TrimSuffix(destPath, TemplateSuffix), data); err != nil {
+				return fmt.Errorf("could not render %s: %w", path, err)
+			}
+			return nil
+		}
+
 		// not a directory – copy file
 		src, err := os.Open(fullPath)
 		if err != nil {
@@ -70,6 +140,14 @@ func (i *Instance) CopyOverwrites() error {
 		return err
 	}
 
+	for _, patch := range patches {
+		target, _ := configpatch.Target(patch)
+		err := configpatch.Apply(filepath.Join(i.OverwritesDir(), patch), filepath.Join(i.McDir(), target))
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -125,3 +203,63 @@ func (i *Instance) CopyLocalSaves() error {
 
 	return nil
 }
+
+// OverwriteFiles returns the paths of all files that `CopyOverwrites` writes to the minecraft dir
+// (including rendered templates and patched files). The paths are relative to `McDir`
+func (i *Instance) OverwriteFiles() ([]string, error) {
+	matcher, err := i.ignoreMatcher(savesIgnore...)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0)
+	seen := make(map[string]bool)
+	err = i.walkOverwrites(matcher, func(path string, fullPath string, info os.FileInfo) error {
+		if info.IsDir() {
+			return nil
+		}
+		if target, ok := configpatch.Target(path); ok {
+			path = target
+		}
+		path = strings.TrimSuffix(path, TemplateSuffix)
+		if !seen[path] {
+			seen[path] = true
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// PackageFiles returns the paths of all overwrites that are included when publishing the modpack.
+// This includes the saves (without player data). The paths are relative to `OverwritesDir`
+func (i *Instance) PackageFiles() ([]string, error) {
+	matcher, err := i.ignoreMatcher(packageSavesIgnore...)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0)
+	err = i.walkOverwrites(matcher, func(path string, fullPath string, info os.FileInfo) error {
+		if !info.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// PackFiles returns the paths of all files that are included when publishing a resource, shader or data pack.
+// These are the files in the package directory (not the overwrites). The paths are relative to `Directory`
+func (i *Instance) PackFiles() ([]string, error) {
+	matcher, err := i.ignoreMatcher("/overwrites/")
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0)
+	err = walkDir(i.Directory, matcher, func(path string, fullPath string, info os.FileInfo) error {
+		if !info.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
@@ -0,0 +1,114 @@
+package instances
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/minepkg/minepkg/pkg/manifest"
+)
+
+func TestCopyOverwrites(t *testing.T) {
+	instance := &Instance{
+		Directory: t.TempDir(),
+		Manifest:  manifest.New(),
+		Lockfile:  manifest.NewLockfile(),
+	}
+	instance.Manifest.Package.Name = "test-pack"
+	instance.Manifest.Vars = map[string]string{"motd": "Hello"}
+	instance.ServerAddress = "example.com:25565"
+
+	files := map[string]string{
+		"config/mod.json":               `{"a": 1}`,
+		"server.properties.tmpl":        "motd={{.Vars.motd}} from {{.Name}}\nserver={{.ServerAddress}}\n",
+		"options.txt":                   "keybind=G\n",
+		"config/other.properties":       "pvp=true\n",
+		"config/other.properties.patch": "pvp=false\n",
+	}
+	for name, content := range files {
+		p := filepath.Join(instance.OverwritesDir(), name)
+		os.MkdirAll(filepath.Dir(p), os.ModePerm)
+		ioutil.WriteFile(p, []byte(content), 0644)
+	}
+	os.MkdirAll(instance.McDir(), os.ModePerm)
+
+	if err := instance.CopyOverwrites(); err != nil {
+		t.Fatal(err)
+	}
+
+	rendered, _ := ioutil.ReadFile(filepath.Join(instance.McDir(), "server.properties"))
+	if string(rendered) != "motd=Hello from test-pack\nserver=example.com:25565\n" {
+		t.Fatalf("unexpected rendered template: %q", rendered)
+	}
+	patched, _ := ioutil.ReadFile(filepath.Join(instance.McDir(), "config", "other.properties"))
+	if string(patched) != "pvp=false\n" {
+		t.Fatalf("unexpected patched file: %q", patched)
+	}
+
+	written, err := instance.OverwriteFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(written)
+	expected := []string{
+		filepath.Join("config", "mod.json"),
+		filepath.Join("config", "other.properties"),
+		"options.txt",
+		"server.properties",
+	}
+	if !reflect.DeepEqual(written, expected) {
+		t.Fatalf("expected %v, got %v", expected, written)
+	}
+}
+
+func TestPackageFiles(t *testing.T) {
+	instance := &Instance{Directory: t.TempDir()}
+	ioutil.WriteFile(instance.IgnorePath(), []byte("logs/\n*.bak\n"), 0644)
+
+	files := []string{
+		"config/mod.json",
+		"config/mod.json.bak",
+		"logs/latest.log",
+		".idea/workspace.xml",
+		"README.md",
+		"saves/world/level.dat",
+		"saves/world/playerdata/player.dat",
+	}
+	for _, name := range files {
+		p := filepath.Join(instance.OverwritesDir(), name)
+		os.MkdirAll(filepath.Dir(p), os.ModePerm)
+		ioutil.WriteFile(p, []byte{}, 0644)
+	}
+
+	packaged, err := instance.PackageFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(packaged)
+	expected := []string{
+		filepath.Join("config", "mod.json"),
+		filepath.Join("saves", "world", "level.dat"),
+	}
+	if !reflect.DeepEqual(packaged, expected) {
+		t.Fatalf("expected %v, got %v", expected, packaged)
+	}
+}
+
+func TestTemplateDataEnv(t *testing.T) {
+	t.Setenv("MINEPKG_VAR_SEED", "42")
+	t.Setenv("MINEPKG_API_KEY", "secret")
+	instance := &Instance{Manifest: manifest.New()}
+
+	env := instance.TemplateData().Env
+	if env["SEED"] != "42" {
+		t.Errorf("expected SEED to be 42, got %q", env["SEED"])
+	}
+	for key := range env {
+		if key == "MINEPKG_API_KEY" || key == "API_KEY" {
+			t.Errorf("%s should not be available in templates", key)
+		}
+	}
+}
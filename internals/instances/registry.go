@@ -0,0 +1,211 @@
+package instances
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml"
+)
+
+// RegistryEntry is a single known instance
+type RegistryEntry struct {
+	// Name is the unique name of this instance. It is the key in `Registry.Instances`
+	Name string `toml:"-"`
+	// Directory is the absolute path of the instance
+	Directory string `toml:"directory"`
+	// LastPlayed is the last time this instance was launched
+	LastPlayed time.Time `toml:"lastPlayed,omitempty"`
+}
+
+// Registry keeps track of all instances on this machine. It is saved as
+// `instances.toml` in the global directory
+type Registry struct {
+	path      string
+	Instances map[string]*RegistryEntry `toml:"instances"`
+}
+
+// OpenRegistry reads the instance registry from the given global directory.
+// A missing registry file results in an empty registry
+func OpenRegistry(globalDir string) (*Registry, error) {
+	registry := &Registry{
+		path:      filepath.Join(globalDir, "instances.toml"),
+		Instances: make(map[string]*RegistryEntry),
+	}
+
+	raw, err := ioutil.ReadFile(registry.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return registry, nil
+		}
+		return nil, err
+	}
+	if err := toml.Unmarshal(raw, registry); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", registry.path, err)
+	}
+	if registry.Instances == nil {
+		registry.Instances = make(map[string]*RegistryEntry)
+	}
+	for name, entry := range registry.Instances {
+		entry.Name = name
+	}
+	return registry, nil
+}
+
+// Save writes the registry to disk
+func (r *Registry) Save() error {
+	if err := os.MkdirAll(filepath.Dir(r.path), os.ModePerm); err != nil {
+		return err
+	}
+	buf, err := toml.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, buf, 0644)
+}
+
+// Get returns the instance with the given name or nil
+func (r *Registry) Get(name string) *RegistryEntry {
+	return r.Instances[name]
+}
+
+// ByDirectory returns the instance in the given directory or nil
+func (r *Registry) ByDirectory(dir string) *RegistryEntry {
+	for _, entry := range r.Instances {
+		if entry.Directory == dir {
+			return entry
+		}
+	}
+	return nil
+}
+
+// Register adds the instance in dir to the registry (if it is not registered already).
+// name is used as the instance name, a number is appended if the name is taken
+func (r *Registry) Register(dir string, name string) *RegistryEntry {
+	if entry := r.ByDirectory(dir); entry != nil {
+		return entry
+	}
+
+	unique := name
+	for n := 2; r.Instances[unique] != nil; n++ {
+		unique = fmt.Sprintf("%s-%d", name, n)
+	}
+	entry := &RegistryEntry{Name: unique, Directory: dir}
+	r.Instances[unique] = entry
+	return entry
+}
+
+// Remove removes the instance from the registry. It does not touch any files
+func (r *Registry) Remove(name string) {
+	delete(r.Instances, name)
+}
+
+// Rename changes the name of an instance
+func (r *Registry) Rename(name string, newName string) error {
+	entry := r.Instances[name]
+	if entry == nil {
+		return fmt.Errorf("instance %s does not exist", name)
+	}
+	if r.Instances[newName] != nil {
+		return fmt.Errorf("instance %s already exists", newName)
+	}
+	delete(r.Instances, name)
+	entry.Name = newName
+	r.Instances[newName] = entry
+	return nil
+}
+
+// Discover registers all instances in instancesDir that are not registered yet
+// and removes entries whose directory does not exist anymore
+func (r *Registry) Discover(instancesDir string) error {
+	for name, entry := range r.Instances {
+		if _, err := os.Stat(entry.Directory); os.IsNotExist(err) {
+			delete(r.Instances, name)
+		}
+	}
+
+	dirs, err := ioutil.ReadDir(instancesDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		r.Register(filepath.Join(instancesDir, dir.Name()), dir.Name())
+	}
+	return nil
+}
+
+// List returns all instances. The most recently played come first
+func (r *Registry) List() []*RegistryEntry {
+	list := make([]*RegistryEntry, 0, len(r.Instances))
+	for _, entry := range r.Instances {
+		list = append(list, entry)
+	}
+	sort.Slice(list, func(a, b int) bool {
+		if !list[a].LastPlayed.Equal(list[b].LastPlayed) {
+			return list[a].LastPlayed.After(list[b].LastPlayed)
+		}
+		return list[a].Name < list[b].Name
+	})
+	return list
+}
+
+// IsManaged returns true if the instance lives in the global instances directory
+// (eg. was created by `minepkg join` or by launching a modpack by name)
+func (e *RegistryEntry) IsManaged(instancesDir string) bool {
+	rel, err := filepath.Rel(instancesDir, e.Directory)
+	return err == nil && !strings.HasPrefix(rel, "..") && rel != "."
+}
+
+// DiskUsage returns the size of all files in the instance directory in bytes.
+// Symlinks (eg. linked mods) are not followed
+func (e *RegistryEntry) DiskUsage() (int64, error) {
+	var size int64
+	err := filepath.Walk(e.Directory, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// RegistryName returns the name this instance should be registered with
+func (i *Instance) RegistryName() string {
+	if filepath.Dir(i.Directory) == i.InstancesDir() {
+		return filepath.Base(i.Directory)
+	}
+	return i.Manifest.Package.Name
+}
+
+// MarkPlayed registers the instance in the global registry (if needed)
+// and sets its last played time to now. Temporary instances are ignored
+func (i *Instance) MarkPlayed() error {
+	registry, err := OpenRegistry(i.GlobalDir)
+	if err != nil {
+		return err
+	}
+	dir, err := filepath.Abs(i.Directory)
+	if err != nil {
+		return err
+	}
+	// temporary instances (eg. from `minepkg try`) are not registered
+	if strings.HasPrefix(dir, filepath.Clean(os.TempDir())+string(filepath.Separator)) {
+		return nil
+	}
+	entry := registry.Register(dir, i.RegistryName())
+	entry.LastPlayed = time.Now()
+	return registry.Save()
+}
@@ -0,0 +1,55 @@
+package instances
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	globalDir := t.TempDir()
+	instancesDir := filepath.Join(globalDir, "instances")
+	os.MkdirAll(filepath.Join(instancesDir, "server.127.0.0.1.pack.fabric"), os.ModePerm)
+
+	registry, err := OpenRegistry(globalDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Discover(instancesDir); err != nil {
+		t.Fatal(err)
+	}
+	if registry.Get("server.127.0.0.1.pack.fabric") == nil {
+		t.Fatal("instance was not discovered")
+	}
+
+	first := registry.Register("/some/project", "pack")
+	second := registry.Register("/other/project", "pack")
+	if first.Name != "pack" || second.Name != "pack-2" {
+		t.Fatalf("expected unique names, got %s and %s", first.Name, second.Name)
+	}
+	if err := registry.Rename("pack-2", "pack"); err == nil {
+		t.Fatal("expected rename to an existing name to fail")
+	}
+	if err := registry.Rename("pack-2", "other"); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := OpenRegistry(globalDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry := reopened.Get("other"); entry == nil || entry.Directory != "/other/project" {
+		t.Fatalf("registry was not saved correctly: %+v", reopened.Instances)
+	}
+
+	// instances with missing directories are removed
+	if err := reopened.Discover(instancesDir); err != nil {
+		t.Fatal(err)
+	}
+	if len(reopened.Instances) != 1 {
+		t.Fatalf("expected only the existing instance to remain, got %+v", reopened.Instances)
+	}
+}
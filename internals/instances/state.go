@@ -0,0 +1,208 @@
+package instances
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/minepkg/minepkg/internals/api"
+	"github.com/pelletier/go-toml"
+)
+
+// StateFile is the file in `McDir` that records which files were placed by minepkg
+const StateFile = ".minepkg-state.toml"
+
+// State records the files minepkg placed in the minecraft directory.
+// Only these files are removed or replaced, files added by the player are left alone
+type State struct {
+	path string
+	// Mods are the names of the files in the mods folder that are managed by minepkg
+	Mods []string `toml:"mods"`
+	// Packs are the resource, shader and data packs that are managed by minepkg.
+	// The paths are relative to `McDir`
+	Packs []string `toml:"packs"`
+	// Extracted are the files extracted from modpack dependencies by package name
+	Extracted map[string]*ExtractedFiles `toml:"extracted,omitempty"`
+}
+
+// StatePath returns the path of the state file
+func (i *Instance) StatePath() string {
+	return filepath.Join(i.McDir(), StateFile)
+}
+
+// LoadState reads the state file. Instances without a state file were set up by an older
+// minepkg version that did not keep track of its files. In that case symlinks and files named like
+// a locked dependency are considered to be managed by minepkg
+func (i *Instance) LoadState() (*State, error) {
+	state := &State{
+		path:      i.StatePath(),
+		Mods:      []string{},
+		Packs:     []string{},
+		Extracted: make(map[string]*ExtractedFiles),
+	}
+
+	raw, err := ioutil.ReadFile(state.path)
+	switch {
+	case os.IsNotExist(err):
+		state.Mods, err = i.legacyManagedMods()
+		return state, err
+	case err != nil:
+		return nil, err
+	}
+	if err := toml.Unmarshal(raw, state); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", StateFile, err)
+	}
+	if state.Extracted == nil {
+		state.Extracted = make(map[string]*ExtractedFiles)
+	}
+	return state, nil
+}
+
+// Save writes the state to disk
+func (s *State) Save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), os.ModePerm); err != nil {
+		return err
+	}
+	s.Mods = uniqueSorted(s.Mods)
+	s.Packs = uniqueSorted(s.Packs)
+
+	buf, err := toml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.path, buf, 0644)
+}
+
+func uniqueSorted(list []string) []string {
+	seen := make(map[string]bool)
+	unique := make([]string, 0, len(list))
+	for _, entry := range list {
+		if !seen[entry] {
+			seen[entry] = true
+			unique = append(unique, entry)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
+
+// OwnsMod returns true if the file in the mods folder is managed by minepkg
+func (s *State) OwnsMod(name string) bool {
+	for _, mod := range s.Mods {
+		if mod == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *Instance) legacyManagedMods() ([]string, error) {
+	files, err := ioutil.ReadDir(i.ModsDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+
+	lockedNames := make(map[string]bool)
+	if i.Lockfile != nil {
+		for _, dep := range i.Lockfile.Dependencies {
+			lockedNames[dep.Filename()] = true
+		}
+	}
+
+	managed := make([]string, 0, len(files))
+	for _, f := range files {
+		if f.Mode()&os.ModeSymlink != 0 || lockedNames[f.Name()] {
+			managed = append(managed, f.Name())
+		}
+	}
+	return managed, nil
+}
+
+// UnmanagedMods returns the names of the files in the mods folder that were not placed by minepkg
+// (eg. jars the player dropped in manually). Mods from the overwrites are not included
+func (i *Instance) UnmanagedMods() ([]string, error) {
+	state, err := i.LoadState()
+	if err != nil {
+		return nil, err
+	}
+	files, err := ioutil.ReadDir(i.ModsDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+	overwrites, err := i.OverwriteFiles()
+	if err != nil {
+		return nil, err
+	}
+	fromOverwrites := make(map[string]bool)
+	for _, path := range overwrites {
+		fromOverwrites[filepath.ToSlash(path)] = true
+	}
+
+	unmanaged := make([]string, 0)
+	for _, f := range files {
+		if f.IsDir() || state.OwnsMod(f.Name()) || fromOverwrites["mods/"+f.Name()] {
+			continue
+		}
+		unmanaged = append(unmanaged, f.Name())
+	}
+	return unmanaged, nil
+}
+
+// ReleaseFinder returns the release of a mod jar.
+// It should return `api.ErrNotFound` for unknown jars
+type ReleaseFinder func(ctx context.Context, jar string) (*api.Release, error)
+
+// AdoptMods looks up the unmanaged mods and adds the matches with their exact version as dependencies
+// to the manifest. The jars are not touched, use `RemoveAdoptedMods` after the releases were installed.
+// The adopted releases are returned by file name
+func (i *Instance) AdoptMods(ctx context.Context, find ReleaseFinder) (map[string]*api.Release, error) {
+	unmanaged, err := i.UnmanagedMods()
+	if err != nil {
+		return nil, err
+	}
+
+	adopted := make(map[string]*api.Release)
+	for _, name := range unmanaged {
+		path := filepath.Join(i.ModsDir(), name)
+		release, err := find(ctx, path)
+		switch {
+		case errors.Is(err, api.ErrNotFound):
+			continue
+		case err != nil:
+			return nil, err
+		}
+
+		i.Manifest.AddDependency(release.Package.Name, release.Package.Version)
+		adopted[name] = release
+	}
+	return adopted, nil
+}
+
+// RemoveAdoptedMods removes the jars returned by `AdoptMods`. They are linked like any other dependency
+// from now on, so this should only be called after their releases were installed.
+// Jars that are managed by minepkg (because the release has the same file name) are kept
+func (i *Instance) RemoveAdoptedMods(adopted map[string]*api.Release) error {
+	state, err := i.LoadState()
+	if err != nil {
+		return err
+	}
+	for name := range adopted {
+		if state.OwnsMod(name) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(i.ModsDir(), name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package instances
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minepkg/minepkg/internals/api"
+	"github.com/minepkg/minepkg/pkg/manifest"
+)
+
+// newTestInstance returns an instance in temporary directories with deps in its lockfile
+func newTestInstance(t *testing.T, deps ...*manifest.DependencyLock) *Instance {
+	t.Helper()
+	instance := &Instance{
+		GlobalDir: t.TempDir(),
+		CacheDir:  t.TempDir(),
+		Directory: t.TempDir(),
+		Manifest:  manifest.New(),
+		Lockfile:  manifest.NewLockfile(),
+	}
+	for _, dep := range deps {
+		instance.Lockfile.AddDependency(dep)
+	}
+	return instance
+}
+
+func TestLinkDependenciesKeepsUserMods(t *testing.T) {
+	dep := &manifest.DependencyLock{
+		Name:    "test-mod",
+		Version: "1.0.0",
+		Type:    manifest.DependencyLockTypeMod,
+		URL:     "https://example.com/test-mod.jar",
+	}
+	instance := newTestInstance(t, dep)
+
+	cached := filepath.Join(instance.PackageCacheDir(), "test-mod", "1.0.0.jar")
+	os.MkdirAll(filepath.Dir(cached), os.ModePerm)
+	ioutil.WriteFile(cached, []byte("jar"), 0644)
+
+	os.MkdirAll(instance.ModsDir(), os.ModePerm)
+	userMod := filepath.Join(instance.ModsDir(), "user-mod.jar")
+	ioutil.WriteFile(userMod, []byte("user"), 0644)
+
+	// run twice to make sure the managed files are replaced
+	for n := 0; n < 2; n++ {
+		if err := instance.LinkDependencies(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := os.Stat(userMod); err != nil {
+		t.Fatalf("expected user mod to be kept: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(instance.ModsDir(), dep.Filename())); err != nil {
+		t.Fatalf("expected dependency to be linked: %v", err)
+	}
+
+	unmanaged, err := instance.UnmanagedMods()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unmanaged) != 1 || unmanaged[0] != "user-mod.jar" {
+		t.Fatalf("expected only user-mod.jar to be unmanaged, got %v", unmanaged)
+	}
+
+	// removed dependencies are cleaned up
+	instance.Lockfile.ClearDependencies()
+	if err := instance.LinkDependencies(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Lstat(filepath.Join(instance.ModsDir(), dep.Filename())); !os.IsNotExist(err) {
+		t.Fatal("expected removed dependency to be unlinked")
+	}
+}
+
+func TestAdoptMods(t *testing.T) {
+	instance := newTestInstance(t)
+	os.MkdirAll(instance.ModsDir(), os.ModePerm)
+	ioutil.WriteFile(filepath.Join(instance.ModsDir(), "known.jar"), []byte("known"), 0644)
+	ioutil.WriteFile(filepath.Join(instance.ModsDir(), "unknown.jar"), []byte("unknown"), 0644)
+
+	find := func(ctx context.Context, jar string) (*api.Release, error) {
+		if filepath.Base(jar) != "known.jar" {
+			return nil, api.ErrNotFound
+		}
+		man := manifest.New()
+		man.Package.Name = "known-mod"
+		man.Package.Version = "1.2.0"
+		return &api.Release{Manifest: man}, nil
+	}
+	adopted, err := instance.AdoptMods(context.Background(), find)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(adopted) != 1 || adopted["known.jar"] == nil {
+		t.Fatalf("expected known.jar to be adopted, got %v", adopted)
+	}
+	if version := instance.Manifest.Dependencies["known-mod"]; version != "1.2.0" {
+		t.Errorf("expected the exact version 1.2.0 in the manifest, got %q", version)
+	}
+	if _, err := os.Stat(filepath.Join(instance.ModsDir(), "known.jar")); err != nil {
+		t.Error("expected the adopted jar to be kept until it is removed")
+	}
+
+	if err := instance.RemoveAdoptedMods(adopted); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(instance.ModsDir(), "known.jar")); !os.IsNotExist(err) {
+		t.Error("expected the adopted jar to be removed")
+	}
+	if _, err := os.Stat(filepath.Join(instance.ModsDir(), "unknown.jar")); err != nil {
+		t.Error("expected the unknown jar to be kept")
+	}
+}
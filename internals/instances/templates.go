@@ -0,0 +1,89 @@
+package instances
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// TemplateSuffix marks overwrites that are rendered with `TemplateData` before they are copied.
+// The suffix is removed from the file name (eg. "server.properties.tmpl" → "server.properties")
+const TemplateSuffix = ".tmpl"
+
+// TemplateEnvPrefix is the prefix of the environment variables that are available in templated overwrites.
+// Other variables are not exposed, they might contain secrets (like `MINEPKG_API_KEY`)
+const TemplateEnvPrefix = "MINEPKG_VAR_"
+
+// TemplateData are the variables available in templated overwrites
+type TemplateData struct {
+	// Name is the package name
+	Name string
+	// Version is the package version
+	Version string
+	// Minecraft is the resolved Minecraft version
+	Minecraft string
+	// FabricLoader is the resolved fabric loader version (if any)
+	FabricLoader string
+	// Directory is the instance directory
+	Directory string
+	// ServerAddress is the address of the server the client joins (if any)
+	ServerAddress string
+	// Vars are the values of the `[vars]` manifest section
+	Vars map[string]string
+	// Env are the environment variables starting with `TemplateEnvPrefix`.
+	// The prefix is removed (`MINEPKG_VAR_SEED` is available as `{{.Env.SEED}}`)
+	Env map[string]string
+}
+
+// TemplateData returns the variables used to render templated overwrites
+func (i *Instance) TemplateData() *TemplateData {
+	data := &TemplateData{
+		Name:          i.Manifest.Package.Name,
+		Version:       i.Manifest.Package.Version,
+		Minecraft:     i.Manifest.Requirements.Minecraft,
+		FabricLoader:  i.Manifest.Requirements.FabricLoader,
+		Directory:     i.Directory,
+		ServerAddress: i.ServerAddress,
+		Vars:          make(map[string]string),
+		Env:           make(map[string]string),
+	}
+
+	// prefer the resolved versions
+	if i.Lockfile != nil && i.Lockfile.HasRequirements() {
+		data.Minecraft = i.Lockfile.MinecraftVersion()
+	}
+	if i.Lockfile != nil && i.Lockfile.Fabric != nil {
+		data.FabricLoader = i.Lockfile.Fabric.FabricLoader
+	}
+
+	for key, value := range i.Manifest.Vars {
+		data.Vars[key] = value
+	}
+	for _, env := range os.Environ() {
+		comp := strings.SplitN(env, "=", 2)
+		if len(comp) == 2 && strings.HasPrefix(comp[0], TemplateEnvPrefix) {
+			data.Env[strings.TrimPrefix(comp[0], TemplateEnvPrefix)] = comp[1]
+		}
+	}
+	return data
+}
+
+// renderTemplate renders the template at src into dest. Unknown variables are an error
+func renderTemplate(src string, dest string, data *TemplateData) error {
+	raw, err := ioutil.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	tmpl, err := template.New(src).Option("missingkey=error").Parse(string(raw))
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return tmpl.Execute(f, data)
+}
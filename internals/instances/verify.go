@@ -0,0 +1,138 @@
+package instances
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/minepkg/minepkg/internals/downloadmgr"
+	"github.com/minepkg/minepkg/internals/minecraft"
+)
+
+// FindCorruptMinecraftFiles rehashes the shared assets, libraries and Minecraft jars
+// and returns download items for every file that does not match its sha1 sum.
+// Corrupt files are removed. Libraries and jars are checked against all launch manifests
+// found in `VersionsDir`
+func (i *Instance) FindCorruptMinecraftFiles() ([]*downloadmgr.HTTPItem, error) {
+	corrupt := make([]*downloadmgr.HTTPItem, 0)
+
+	// assets are named after their sha1 sum
+	objectsDir := filepath.Join(i.AssetsDir(), "objects")
+	err := filepath.WalkDir(objectsDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		asset := minecraft.AssetObject{Hash: d.Name()}
+		if len(asset.Hash) < 2 {
+			return nil
+		}
+		if item, err := verifySha1(path, asset.Hash, asset.DownloadURL()); err != nil || item != nil {
+			if item != nil {
+				corrupt = append(corrupt, item)
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	manifests, err := i.cachedLaunchManifests()
+	if err != nil {
+		return nil, err
+	}
+
+	checked := make(map[string]bool)
+	for _, man := range manifests {
+		for _, lib := range man.Libraries.Required() {
+			path := filepath.Join(i.LibrariesDir(), lib.Filepath())
+			if checked[path] || lib.Sha1() == "" {
+				continue
+			}
+			checked[path] = true
+			item, err := verifySha1(path, lib.Sha1(), lib.DownloadURL())
+			if err != nil {
+				return nil, err
+			}
+			if item != nil {
+				corrupt = append(corrupt, item)
+			}
+		}
+
+		client := man.Downloads.Client
+		if client.Sha1 == "" || client.URL == "" {
+			continue
+		}
+		path := filepath.Join(i.VersionsDir(), man.MinecraftVersion(), man.JarName())
+		if checked[path] {
+			continue
+		}
+		checked[path] = true
+		item, err := verifySha1(path, client.Sha1, client.URL)
+		if err != nil {
+			return nil, err
+		}
+		if item != nil {
+			corrupt = append(corrupt, item)
+		}
+	}
+
+	return corrupt, nil
+}
+
+// cachedLaunchManifests returns all launch manifests in `VersionsDir`
+func (i *Instance) cachedLaunchManifests() ([]*minecraft.LaunchManifest, error) {
+	versions, err := ioutil.ReadDir(i.VersionsDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	manifests := make([]*minecraft.LaunchManifest, 0, len(versions))
+	for _, version := range versions {
+		if !version.IsDir() {
+			continue
+		}
+		buf, err := ioutil.ReadFile(filepath.Join(i.VersionsDir(), version.Name(), version.Name()+".json"))
+		if err != nil {
+			continue
+		}
+		man := &minecraft.LaunchManifest{}
+		if err := json.Unmarshal(buf, man); err != nil {
+			continue
+		}
+		manifests = append(manifests, man)
+	}
+	return manifests, nil
+}
+
+// verifySha1 returns a download item if the file at path exists and does not match sha
+func verifySha1(path string, sha string, url string) (*downloadmgr.HTTPItem, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	err := downloadmgr.CheckSha1(sha, path)
+	var invalidSha *downloadmgr.ErrInvalidSha
+	switch {
+	case errors.As(err, &invalidSha):
+		item := downloadmgr.NewHTTPItem(url, path)
+		item.Sha1 = sha
+		return item, nil
+	case err != nil:
+		return nil, err
+	}
+	return nil, nil
+}
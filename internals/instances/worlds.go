@@ -0,0 +1,62 @@
+package instances
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/minepkg/minepkg/internals/minecraft"
+)
+
+// BackupsDir is the path where world backups are stored. This is the `backups` subfolder
+func (i *Instance) BackupsDir() string {
+	return filepath.Join(i.Directory, "backups")
+}
+
+// SavesDir is the path of the client savegames. This is the `minecraft/saves` subfolder
+func (i *Instance) SavesDir() string {
+	return filepath.Join(i.McDir(), "saves")
+}
+
+// ServerWorldDir returns the world directory of the server. It is read from the `level-name`
+// in the `server.properties` and defaults to `minecraft/world`
+func (i *Instance) ServerWorldDir() string {
+	name := "world"
+	raw, err := ioutil.ReadFile(filepath.Join(i.McDir(), "server.properties"))
+	if err == nil {
+		if levelName := minecraft.ParseServerProps(raw)["level-name"]; levelName != "" {
+			name = levelName
+		}
+	}
+	return filepath.Join(i.McDir(), name)
+}
+
+// WorldDirs returns the server world (if server is true) or every client savegame that exists
+func (i *Instance) WorldDirs(server bool) ([]string, error) {
+	if server {
+		dir := i.ServerWorldDir()
+		if _, err := os.Stat(dir); err != nil {
+			if os.IsNotExist(err) {
+				return []string{}, nil
+			}
+			return nil, err
+		}
+		return []string{dir}, nil
+	}
+
+	entries, err := ioutil.ReadDir(i.SavesDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+	dirs := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			dirs = append(dirs, filepath.Join(i.SavesDir(), entry.Name()))
+		}
+	}
+	return dirs, nil
+}
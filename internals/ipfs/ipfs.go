@@ -0,0 +1,55 @@
+// Package ipfs fetches packages from IPFS using a gateway or the API of a local node
+package ipfs
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/minepkg/minepkg/internals/downloadmgr"
+)
+
+// Client fetches files from IPFS. Gateway and API are optional, IPFS is not used if both are empty
+type Client struct {
+	// Gateway is an IPFS http gateway (eg. "https://ipfs.io" or "http://127.0.0.1:8080")
+	Gateway string
+	// API is the RPC API of a local IPFS node (eg. "http://127.0.0.1:5001")
+	API string
+}
+
+// Default is the ipfs client used by minepkg. IPFS is disabled unless configured
+var Default = &Client{}
+
+// Enabled returns true if a gateway or API is configured
+func (c *Client) Enabled() bool {
+	return c.Gateway != "" || c.API != ""
+}
+
+// Downloaders returns download items for the IPFS file with the given hash.
+// The local node API is tried before the gateway. The file is verified with sha256 if set
+func (c *Client) Downloaders(hash string, target string, sha256 string) []downloadmgr.Downloader {
+	downloaders := []downloadmgr.Downloader{}
+	if hash == "" {
+		return downloaders
+	}
+
+	if c.API != "" {
+		// the RPC API only accepts POST requests
+		downloaders = append(downloaders, &downloadmgr.HTTPItem{
+			Method: http.MethodPost,
+			URL:    strings.TrimSuffix(c.API, "/") + "/api/v0/cat?arg=" + url.QueryEscape(hash),
+			Target: target,
+			Sha256: sha256,
+		})
+	}
+
+	if c.Gateway != "" {
+		downloaders = append(downloaders, &downloadmgr.HTTPItem{
+			URL:    strings.TrimSuffix(c.Gateway, "/") + "/ipfs/" + hash,
+			Target: target,
+			Sha256: sha256,
+		})
+	}
+
+	return downloaders
+}
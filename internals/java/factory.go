@@ -8,6 +8,8 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+
+	"github.com/minepkg/minepkg/internals/mirrors"
 )
 
 var (
@@ -25,7 +27,7 @@ type Factory struct {
 func NewFactory(baseDir string) *Factory {
 	return &Factory{
 		baseDir,
-		http.DefaultClient,
+		mirrors.Default.Client(),
 	}
 }
 
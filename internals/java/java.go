@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	archiver "github.com/mholt/archiver/v3"
+	"github.com/minepkg/minepkg/internals/mirrors"
 )
 
 type Java struct {
@@ -34,6 +35,11 @@ func (j *Java) Bin() string {
 	return filepath.Join(j.dir, bin)
 }
 
+// Dir returns the directory this java version is installed in
+func (j *Java) Dir() string {
+	return j.dir
+}
+
 func (j *Java) NeedsDownloading() bool {
 	return j.needsDownloading
 }
@@ -98,8 +104,7 @@ func (j *Java) Update(ctx context.Context) error {
 
 func (j *Java) download(ctx context.Context) (*os.File, error) {
 	url := j.downloadURL()
-	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
-	res, err := http.DefaultClient.Do(req)
+	res, err := mirrors.Default.Get(ctx, http.DefaultClient, url)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,144 @@
+// Package lancache shares the local package, library and asset caches with other
+// minepkg installations in the same network
+package lancache
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultPort is the port used by `minepkg cache serve`
+const DefaultPort = 27894
+
+// SharedDirs are the subdirectories of the cache directory that are shared with peers
+var SharedDirs = []string{"cache", "libraries", "assets", "versions"}
+
+// Server serves the shared directories of CacheDir over HTTP
+type Server struct {
+	// CacheDir is the minepkg cache directory. on linux this usually is $HOME/.cache/minepkg
+	CacheDir string
+	// OnServe is called for every successfully served file (optional)
+	OnServe func(r *http.Request, file string)
+}
+
+// ServeHTTP serves a single file. Directory listings are not supported
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	cleaned := path.Clean("/" + r.URL.Path)
+	if !isShared(cleaned) {
+		http.NotFound(w, r)
+		return
+	}
+
+	file := filepath.Join(s.CacheDir, filepath.FromSlash(cleaned))
+	stat, err := os.Stat(file)
+	if err != nil || stat.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	if s.OnServe != nil {
+		s.OnServe(r, file)
+	}
+	http.ServeFile(w, r, file)
+}
+
+// ListenAndServe starts serving on the given address (eg. ":27894")
+func (s *Server) ListenAndServe(addr string) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return server.ListenAndServe()
+}
+
+func isShared(cleaned string) bool {
+	for _, dir := range SharedDirs {
+		if strings.HasPrefix(cleaned, "/"+dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Peer is another machine running `minepkg cache serve`
+type Peer struct {
+	// URL is the base url of the peer (eg. "http://192.168.1.5:27894")
+	URL string
+	// CacheDir is the local cache directory. Only files downloaded into
+	// this directory can be fetched from the peer
+	CacheDir string
+	// OnUnreachable is called if the first ping of `Reachable` fails (optional)
+	OnUnreachable func(err error)
+
+	pingOnce  sync.Once
+	reachable bool
+}
+
+// DefaultPeer is the peer used for all downloads. It is nil unless configured
+var DefaultPeer *Peer
+
+// NewPeer returns a new peer. A scheme and the default port are added to url if missing
+func NewPeer(url string, cacheDir string) *Peer {
+	if !strings.Contains(url, "://") {
+		url = "http://" + url
+	}
+	if strings.Count(url, ":") == 1 {
+		url = fmt.Sprintf("%s:%d", url, DefaultPort)
+	}
+	return &Peer{URL: strings.TrimSuffix(url, "/"), CacheDir: cacheDir}
+}
+
+// URLFor returns the URL on the peer for a file that should be downloaded to target.
+// The second return value is false if the file can not be fetched from the peer
+func (p *Peer) URLFor(target string) (string, bool) {
+	rel, err := filepath.Rel(p.CacheDir, target)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", false
+	}
+	rel = "/" + filepath.ToSlash(rel)
+	if !isShared(rel) {
+		return "", false
+	}
+	return p.URL + rel, true
+}
+
+// Reachable pings the peer on the first call. The result is reused for all later calls
+func (p *Peer) Reachable() bool {
+	p.pingOnce.Do(func() {
+		err := p.Ping(context.Background())
+		p.reachable = err == nil
+		if err != nil && p.OnUnreachable != nil {
+			p.OnUnreachable(err)
+		}
+	})
+	return p.reachable
+}
+
+// Ping checks if the peer is reachable
+func (p *Peer) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, p.URL+"/", nil)
+	if err != nil {
+		return err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
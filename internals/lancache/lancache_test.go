@@ -0,0 +1,62 @@
+package lancache
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestServer_ServeHTTP(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "libraries", "org"), os.ModePerm)
+	ioutil.WriteFile(filepath.Join(dir, "libraries", "org", "lib.jar"), []byte("jar"), 0644)
+	os.MkdirAll(filepath.Join(dir, "java"), os.ModePerm)
+	ioutil.WriteFile(filepath.Join(dir, "java", "bin"), []byte("java"), 0644)
+
+	srv := httptest.NewServer(&Server{CacheDir: dir})
+	defer srv.Close()
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/libraries/org/lib.jar", http.StatusOK},
+		{"/libraries/org/", http.StatusNotFound},
+		{"/java/bin", http.StatusNotFound},
+		{"/libraries/../java/bin", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			res, err := http.Get(srv.URL + tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res.Body.Close()
+			if res.StatusCode != tt.want {
+				t.Errorf("GET %s = %d, want %d", tt.path, res.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestPeer_URLFor(t *testing.T) {
+	peer := NewPeer("192.168.1.5", "/home/user/.cache/minepkg")
+	if peer.URL != "http://192.168.1.5:27894" {
+		t.Fatalf("unexpected peer url %s", peer.URL)
+	}
+
+	got, ok := peer.URLFor("/home/user/.cache/minepkg/assets/objects/ab/abcd")
+	if !ok || got != "http://192.168.1.5:27894/assets/objects/ab/abcd" {
+		t.Errorf("URLFor() = %s, %v", got, ok)
+	}
+
+	if _, ok := peer.URLFor("/home/user/.cache/minepkg/java/bin"); ok {
+		t.Error("java dir should not be shared")
+	}
+	if _, ok := peer.URLFor("/tmp/somewhere/else"); ok {
+		t.Error("files outside the cache dir should not be fetched from the peer")
+	}
+}
@@ -0,0 +1,115 @@
+package launcher
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// savedLine matches the log line of the server after a save. Chat messages do not match,
+// because they are prefixed with the player name
+var savedLine = regexp.MustCompile(`^\[[\d:]+\] \[Server thread/INFO\](: | \(Minecraft\) )Saved the game$`)
+
+// ErrSaveTimeout is returned by `ServerConsole.SaveAll` if the server did not confirm the save in time
+var ErrSaveTimeout = errors.New("server did not confirm the save in time")
+
+// ServerConsole sits between the terminal and a minecraft server. The terminal input is
+// forwarded to the server and commands can be sent in between
+type ServerConsole struct {
+	// stdin is passed to the server. It is a *os.File, so the server can be waited
+	// for without closing the input
+	stdin  *os.File
+	input  *os.File
+	output io.Writer
+
+	inputMu sync.Mutex
+	line    []byte
+	saved   chan struct{}
+}
+
+// NewServerConsole creates a console that forwards os.Stdin to the server and its output to os.Stdout
+func NewServerConsole() (*ServerConsole, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	c := &ServerConsole{
+		stdin:  r,
+		input:  w,
+		output: os.Stdout,
+		saved:  make(chan struct{}, 1),
+	}
+	go c.forward(os.Stdin)
+	return c, nil
+}
+
+// Stdin should be used as the stdin of the server
+func (c *ServerConsole) Stdin() io.Reader {
+	return c.stdin
+}
+
+// forward passes the terminal input to the server line by line, so it is never mixed with commands
+func (c *ServerConsole) forward(terminal io.Reader) {
+	reader := bufio.NewReader(terminal)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) != 0 {
+			c.inputMu.Lock()
+			c.input.Write(line)
+			c.inputMu.Unlock()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Command sends a command (eg. "save-off") to the server
+func (c *ServerConsole) Command(command string) error {
+	c.inputMu.Lock()
+	defer c.inputMu.Unlock()
+	_, err := c.input.Write([]byte(command + "\n"))
+	return err
+}
+
+// SaveAll sends "save-all flush" and waits until the server confirms that the world was saved
+func (c *ServerConsole) SaveAll(timeout time.Duration) error {
+	// discard confirmations of saves that were not triggered by us
+	select {
+	case <-c.saved:
+	default:
+	}
+	if err := c.Command("save-all flush"); err != nil {
+		return err
+	}
+	select {
+	case <-c.saved:
+		return nil
+	case <-time.After(timeout):
+		return ErrSaveTimeout
+	}
+}
+
+// Write passes the server output to the terminal. It should be used as the stdout of the server
+func (c *ServerConsole) Write(p []byte) (int, error) {
+	c.line = append(c.line, p...)
+	for {
+		end := bytes.IndexByte(c.line, '\n')
+		if end == -1 {
+			break
+		}
+		if savedLine.Match(bytes.TrimRight(c.line[:end], "\r")) {
+			select {
+			case c.saved <- struct{}{}:
+			default:
+			}
+		}
+		c.line = c.line[end+1:]
+	}
+	return c.output.Write(p)
+}
@@ -0,0 +1,36 @@
+package launcher
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestServerConsoleSaved(t *testing.T) {
+	tests := []struct {
+		line  string
+		saved bool
+	}{
+		{"[12:00:01] [Server thread/INFO]: Saved the game\n", true},
+		{"[12:00:01] [Server thread/INFO]: Saved the game\r\n", true},
+		{"[12:00:01] [Server thread/INFO] (Minecraft) Saved the game\n", true},
+		{"[12:00:01] [Server thread/INFO]: <Steve> Saved the game\n", false},
+		{"[12:00:01] [Server thread/INFO]: <Steve> [12:00:01] [Server thread/INFO]: Saved the game\n", false},
+		{"[12:00:01] [Server thread/INFO]: Saved the game, probably\n", false},
+	}
+	for _, tt := range tests {
+		c := &ServerConsole{output: ioutil.Discard, saved: make(chan struct{}, 1)}
+		// the line is written in two parts
+		c.Write([]byte(tt.line[:10]))
+		c.Write([]byte(tt.line[10:]))
+
+		saved := false
+		select {
+		case <-c.saved:
+			saved = true
+		default:
+		}
+		if saved != tt.saved {
+			t.Errorf("%q: saved is %v, want %v", tt.line, saved, tt.saved)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jwalton/gchalk"
 	"github.com/minepkg/minepkg/internals/downloadmgr"
+	"github.com/minepkg/minepkg/internals/instances"
 	"github.com/minepkg/minepkg/internals/minecraft"
 	"github.com/spf13/viper"
 )
@@ -55,6 +56,12 @@ func (l *Launcher) Prepare() error {
 		return err
 	}
 
+	if err := l.warnUnmanagedMods(); err != nil {
+		return err
+	}
+	l.warnKeptFiles()
+	l.warnConflictFiles()
+
 	if l.ServerMode {
 		fmt.Println(pipeText.Render("\nPreparing server"))
 		l.prepareServer()
@@ -73,6 +80,49 @@ func (l *Launcher) Prepare() error {
 	return nil
 }
 
+// warnUnmanagedMods lists the mods that were added to the mods folder by hand. They are kept
+func (l *Launcher) warnUnmanagedMods() error {
+	unmanaged, err := l.Instance.UnmanagedMods()
+	if err != nil || len(unmanaged) == 0 {
+		return err
+	}
+	fmt.Println(gchalk.Yellow(fmt.Sprintf("Keeping %d mods that were not installed by minepkg:", len(unmanaged))))
+	for _, name := range unmanaged {
+		fmt.Println(gchalk.Yellow("  - " + name))
+	}
+	fmt.Println(gchalk.Gray("Run \"minepkg install --adopt\" to add them to your minepkg.toml"))
+	fmt.Println("│")
+	return nil
+}
+
+// warnKeptFiles lists the files of old modpack versions that were not removed because the player changed them
+func (l *Launcher) warnKeptFiles() {
+	kept := l.Instance.KeptFiles()
+	if len(kept) == 0 {
+		return
+	}
+	fmt.Println(gchalk.Yellow(fmt.Sprintf("Keeping %d changed files that are not part of the modpack anymore:", len(kept))))
+	for _, path := range kept {
+		fmt.Println(gchalk.Yellow("  - " + path))
+	}
+	fmt.Println(gchalk.Gray("Delete them if you do not need them anymore"))
+	fmt.Println("│")
+}
+
+// warnConflictFiles lists the files where the changes of the player could not be merged with the modpack update
+func (l *Launcher) warnConflictFiles() {
+	conflicts := l.Instance.ConflictFiles()
+	if len(conflicts) == 0 {
+		return
+	}
+	fmt.Println(gchalk.Yellow(fmt.Sprintf("Could not merge your changes of %d files with the modpack update:", len(conflicts))))
+	for _, path := range conflicts {
+		fmt.Println(gchalk.Yellow("  - " + path))
+	}
+	fmt.Println(gchalk.Gray(fmt.Sprintf("Your files were kept. The new versions were saved next to them (ending with %s)", instances.ConflictSuffix)))
+	fmt.Println("│")
+}
+
 // prepareRequirements will update the requirements section
 // in the lockfile if needed
 func (l Launcher) prepareRequirements() (bool, error) {
@@ -137,6 +187,12 @@ func (l *Launcher) prepareJavaBg(ctx context.Context) chan error {
 // passing true as the second parameter will make sure to check for available updates
 func (l *Launcher) prepareDependencies(ctx context.Context, force bool) error {
 	instance := l.Instance
+	// linked mods are marked in the dependency list
+	links, err := instance.Links()
+	if err != nil {
+		return err
+	}
+
 	// resolve dependencies
 	outdatedDependencies, err := instance.AreDependenciesOutdated()
 	if err != nil {
@@ -147,14 +203,14 @@ func (l *Launcher) prepareDependencies(ctx context.Context, force bool) error {
 	fmt.Print(pipeText.Render(gchalk.BgGray("Dependencies")))
 	if force || l.ForceUpdate || outdatedDependencies {
 		fmt.Print(gchalk.Gray("(updating)\n"))
-		if err := l.fetchDependencies(ctx); err != nil {
+		if err := l.fetchDependencies(ctx, links); err != nil {
 			return err
 		}
 		instance.SaveLockfile()
 	} else {
 		fmt.Println()
 		for _, dependency := range instance.Lockfile.Dependencies {
-			fmt.Println(dependencyLine(dependency))
+			fmt.Println(dependencyLine(dependency, links[dependency.Name]))
 		}
 	}
 	fmt.Println("│")
@@ -175,7 +231,9 @@ func (l *Launcher) prepareMinecraft(ctx context.Context) error {
 	// TODO move more logic to internals
 	mainJar := filepath.Join(l.Instance.VersionsDir(), l.LaunchManifest.MinecraftVersion(), l.LaunchManifest.JarName())
 	if _, err := os.Stat(mainJar); os.IsNotExist(err) {
-		mgr.Add(downloadmgr.NewHTTPItem(l.LaunchManifest.Downloads.Client.URL, mainJar))
+		item := downloadmgr.NewHTTPItem(l.LaunchManifest.Downloads.Client.URL, mainJar)
+		item.Sha1 = l.LaunchManifest.Downloads.Client.Sha1
+		mgr.Add(item)
 	}
 
 	if !l.ServerMode {
@@ -186,7 +244,9 @@ func (l *Launcher) prepareMinecraft(ctx context.Context) error {
 
 		for _, asset := range missingAssets {
 			target := filepath.Join(instance.CacheDir, "assets/objects", asset.UnixPath())
-			mgr.Add(downloadmgr.NewHTTPItem(asset.DownloadURL(), target))
+			item := downloadmgr.NewHTTPItem(asset.DownloadURL(), target)
+			item.Sha1 = asset.Hash
+			mgr.Add(item)
 		}
 	}
 
@@ -197,7 +257,9 @@ func (l *Launcher) prepareMinecraft(ctx context.Context) error {
 
 	for _, lib := range missingLibs {
 		target := filepath.Join(instance.CacheDir, "libraries", lib.Filepath())
-		mgr.Add(downloadmgr.NewHTTPItem(lib.DownloadURL(), target))
+		item := downloadmgr.NewHTTPItem(lib.DownloadURL(), target)
+		item.Sha1 = lib.Sha1()
+		mgr.Add(item)
 	}
 
 	if err = mgr.Start(ctx); err != nil {
@@ -267,7 +329,7 @@ func (l *Launcher) printOutro() {
 	fmt.Println("│ Java " + javaDir)
 }
 
-func (c *Launcher) fetchDependencies(ctx context.Context) error {
+func (c *Launcher) fetchDependencies(ctx context.Context, links map[string]bool) error {
 	instance := c.Instance
 
 	resolver, err := instance.GetResolver(ctx)
@@ -283,7 +345,7 @@ func (c *Launcher) fetchDependencies(ctx context.Context) error {
 
 	for resolved := range sub {
 		instance.Lockfile.AddDependency(resolved.Lock())
-		fmt.Println(dependencyLine(resolved.Lock()))
+		fmt.Println(dependencyLine(resolved.Lock(), links[resolved.Lock().Name]))
 	}
 
 	if err := <-resolverErrorC; err != nil {
@@ -42,6 +42,10 @@ func (c *Launcher) Run(opts *instances.LaunchOptions) error {
 		return err
 	}
 
+	if err := c.Instance.MarkPlayed(); err != nil {
+		fmt.Println(gchalk.Gray("│ could not update instance registry: " + err.Error()))
+	}
+
 	c.Cmd = cmd
 
 	err = func() error {
@@ -9,7 +9,7 @@ import (
 	"github.com/minepkg/minepkg/pkg/manifest"
 )
 
-func dependencyLine(dependency *manifest.DependencyLock) string {
+func dependencyLine(dependency *manifest.DependencyLock, linked bool) string {
 	border := lipgloss.Border{
 		Left: "├│",
 	}
@@ -22,6 +22,12 @@ func dependencyLine(dependency *manifest.DependencyLock) string {
 	} else if len(version) == 2 {
 		prettyVersion += gchalk.Gray("-" + version[1])
 	}
+	if dependency.Provider == "workspace" {
+		prettyVersion += gchalk.Gray(" (workspace)")
+	}
+	if linked {
+		prettyVersion = gchalk.Gray(prettyVersion) + gchalk.Yellow(" (linked)")
+	}
 
 	name := dependency.Name
 	if dependency.Version == "none" {
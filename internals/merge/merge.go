@@ -0,0 +1,169 @@
+// Package merge implements a line based three-way merge (like diff3).
+// It is used to keep the changes a player made to a config file when a modpack ships a new version of it
+package merge
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+)
+
+// maxLines limits the size of merged files. The diff needs `len(a) * len(b)` memory
+const maxLines = 5000
+
+// textExtensions are the file extensions that are merged line by line
+var textExtensions = map[string]bool{
+	".cfg":        true,
+	".conf":       true,
+	".ini":        true,
+	".js":         true,
+	".json":       true,
+	".json5":      true,
+	".mcmeta":     true,
+	".properties": true,
+	".snbt":       true,
+	".toml":       true,
+	".txt":        true,
+	".yaml":       true,
+	".yml":        true,
+	".zs":         true,
+}
+
+// Mergeable returns true if the file at path is a text file that can be merged
+func Mergeable(path string) bool {
+	return textExtensions[strings.ToLower(filepath.Ext(path))]
+}
+
+// ThreeWay merges the changes from base to ours and from base to theirs.
+// ok is false if both sides changed the same lines differently (a conflict)
+func ThreeWay(base []byte, ours []byte, theirs []byte) (merged []byte, ok bool) {
+	o, a, b := splitLines(base), splitLines(ours), splitLines(theirs)
+	if len(o) > maxLines || len(a) > maxLines || len(b) > maxLines {
+		return nil, false
+	}
+	matchA := match(o, a)
+	matchB := match(o, b)
+
+	out := make([]string, 0, len(a)+len(b))
+	i, ia, ib := 0, 0, 0
+	for {
+		// lines that are unchanged on both sides
+		for i < len(o) && matchA[i] == ia && matchB[i] == ib {
+			out = append(out, o[i])
+			i, ia, ib = i+1, ia+1, ib+1
+		}
+		if i == len(o) && ia == len(a) && ib == len(b) {
+			break
+		}
+
+		// find the next base line that is still present on both sides
+		next := i
+		for next < len(o) && (matchA[next] == -1 || matchB[next] == -1) {
+			next++
+		}
+		endA, endB := len(a), len(b)
+		if next < len(o) {
+			endA, endB = matchA[next], matchB[next]
+		}
+
+		resolved, ok := resolve(o[i:next], a[ia:endA], b[ib:endB])
+		if !ok {
+			return nil, false
+		}
+		out = append(out, resolved...)
+		i, ia, ib = next, endA, endB
+	}
+	return []byte(strings.Join(out, "")), true
+}
+
+// resolve merges a chunk that was changed on at least one side
+func resolve(o []string, a []string, b []string) ([]string, bool) {
+	switch {
+	case equal(a, o):
+		return b, true
+	case equal(b, o), equal(a, b):
+		return a, true
+	case len(o) != len(a) || len(o) != len(b):
+		return nil, false
+	}
+
+	// lines were replaced in place (common for "key=value" configs). they are resolved one by one
+	resolved := make([]string, len(o))
+	for n := range o {
+		switch {
+		case a[n] == o[n]:
+			resolved[n] = b[n]
+		case b[n] == o[n], a[n] == b[n]:
+			resolved[n] = a[n]
+		default:
+			return nil, false
+		}
+	}
+	return resolved, true
+}
+
+// splitLines splits s into lines. The line endings are kept
+func splitLines(s []byte) []string {
+	lines := make([]string, 0, bytes.Count(s, []byte("\n"))+1)
+	for len(s) != 0 {
+		end := bytes.IndexByte(s, '\n') + 1
+		if end == 0 {
+			end = len(s)
+		}
+		lines = append(lines, string(s[:end]))
+		s = s[end:]
+	}
+	return lines
+}
+
+// match returns the index in b of every line in a that is part of the longest common subsequence (or -1)
+func match(a []string, b []string) []int {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	matches := make([]int, len(a))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			matches[i] = j
+			i, j = i+1, j+1
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			matches[i] = -1
+			i++
+		default:
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		matches[i] = -1
+	}
+	return matches
+}
+
+func equal(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+package merge
+
+import "testing"
+
+func TestThreeWay(t *testing.T) {
+	base := "fov=70\nrenderDistance=8\nlang=en_us\n"
+
+	tests := []struct {
+		name   string
+		ours   string
+		theirs string
+		want   string
+		ok     bool
+	}{
+		{
+			name:   "only ours changed",
+			ours:   "fov=90\nrenderDistance=8\nlang=en_us\n",
+			theirs: base,
+			want:   "fov=90\nrenderDistance=8\nlang=en_us\n",
+			ok:     true,
+		},
+		{
+			name:   "only theirs changed",
+			ours:   base,
+			theirs: "fov=70\nrenderDistance=12\nlang=en_us\n",
+			want:   "fov=70\nrenderDistance=12\nlang=en_us\n",
+			ok:     true,
+		},
+		{
+			name:   "different lines changed",
+			ours:   "fov=90\nrenderDistance=8\nlang=en_us\n",
+			theirs: "fov=70\nrenderDistance=12\nlang=en_us\nsound=on\n",
+			want:   "fov=90\nrenderDistance=12\nlang=en_us\nsound=on\n",
+			ok:     true,
+		},
+		{
+			name:   "same change on both sides",
+			ours:   "fov=70\nrenderDistance=12\nlang=en_us\n",
+			theirs: "fov=70\nrenderDistance=12\nlang=en_us\n",
+			want:   "fov=70\nrenderDistance=12\nlang=en_us\n",
+			ok:     true,
+		},
+		{
+			name:   "removed and inserted lines",
+			ours:   "fov=70\nrenderDistance=8\n",
+			theirs: "gamma=1\nfov=70\nrenderDistance=8\nlang=en_us\n",
+			want:   "gamma=1\nfov=70\nrenderDistance=8\n",
+			ok:     true,
+		},
+		{
+			name:   "conflict",
+			ours:   "fov=90\nrenderDistance=8\nlang=en_us\n",
+			theirs: "fov=100\nrenderDistance=8\nlang=en_us\n",
+			ok:     false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged, ok := ThreeWay([]byte(base), []byte(test.ours), []byte(test.theirs))
+			if ok != test.ok {
+				t.Fatalf("expected ok to be %v", test.ok)
+			}
+			if ok && string(merged) != test.want {
+				t.Fatalf("expected:\n%s\ngot:\n%s", test.want, merged)
+			}
+		})
+	}
+}
+
+func TestMergeable(t *testing.T) {
+	if !Mergeable("config/sodium-options.json") || !Mergeable("options.txt") {
+		t.Error("expected text configs to be mergeable")
+	}
+	if Mergeable("resourcepacks/textures.zip") {
+		t.Error("expected zip files not to be mergeable")
+	}
+}
@@ -104,6 +104,20 @@ func (l *Lib) DownloadURL() string {
 	}
 }
 
+// Sha1 returns the sha1 sum of this library. It is empty for libraries
+// that do not include download information (eg. fabric libraries)
+func (l *Lib) Sha1() string {
+	osName := runtime.GOOS
+	if osName == "darwin" {
+		osName = "osx"
+	}
+
+	if l.Natives[osName] != "" {
+		return l.Downloads.Classifiers[l.Natives[osName]].Sha1
+	}
+	return l.Downloads.Artifact.Sha1
+}
+
 type artifact struct {
 	Path string      `json:"path"`
 	Sha1 string      `json:"sha1"`
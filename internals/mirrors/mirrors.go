@@ -0,0 +1,169 @@
+// Package mirrors rewrites download URLs to configured mirror hosts
+package mirrors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Mirrors maps a host (eg. "libraries.minecraft.net") to a list of base URLs
+// that should be tried (in order) before the original host.
+type Mirrors map[string][]string
+
+// Presets are well known mirror configurations that can be selected by name.
+// User defined presets are added to this map
+var Presets = map[string]Mirrors{
+	// BMCLAPI mirrors Mojang and Fabric downloads. See https://bmclapidoc.bangbang93.com/
+	"bmclapi": {
+		"launchermeta.mojang.com":          {"https://bmclapi2.bangbang93.com"},
+		"launcher.mojang.com":              {"https://bmclapi2.bangbang93.com"},
+		"piston-meta.mojang.com":           {"https://bmclapi2.bangbang93.com"},
+		"piston-data.mojang.com":           {"https://bmclapi2.bangbang93.com"},
+		"resources.download.minecraft.net": {"https://bmclapi2.bangbang93.com/assets"},
+		"libraries.minecraft.net":          {"https://bmclapi2.bangbang93.com/maven"},
+		"meta.fabricmc.net":                {"https://bmclapi2.bangbang93.com/fabric-meta"},
+		"maven.fabricmc.net":               {"https://bmclapi2.bangbang93.com/maven"},
+	},
+}
+
+// Default is the mirror configuration used by minepkg. It is empty unless configured
+var Default = Mirrors{}
+
+// Preset returns the preset with the given name
+func Preset(name string) (Mirrors, error) {
+	preset, ok := Presets[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("mirror preset \"%s\" does not exist", name)
+	}
+	return preset, nil
+}
+
+// Merge adds all mirrors of other to m. Mirrors of other are tried first
+func (m Mirrors) Merge(other Mirrors) {
+	for host, bases := range other {
+		m[host] = append(append([]string{}, bases...), m[host]...)
+	}
+}
+
+// URLs returns the mirrored URLs for rawURL in the order they should be tried.
+// The original URL is always the last entry
+func (m Mirrors) URLs(rawURL string) []string {
+	scheme := strings.Index(rawURL, "://")
+	if scheme == -1 {
+		return []string{rawURL}
+	}
+	hostAndPath := rawURL[scheme+3:]
+	host := hostAndPath
+	path := ""
+	if slash := strings.Index(hostAndPath, "/"); slash != -1 {
+		host = hostAndPath[:slash]
+		path = hostAndPath[slash:]
+	}
+
+	bases := m[strings.ToLower(host)]
+	urls := make([]string, 0, len(bases)+1)
+	for _, base := range bases {
+		urls = append(urls, strings.TrimSuffix(base, "/")+path)
+	}
+	return append(urls, rawURL)
+}
+
+// Rewrite returns the first URL that should be tried for rawURL
+func (m Mirrors) Rewrite(rawURL string) string {
+	return m.URLs(rawURL)[0]
+}
+
+// Get requests rawURL with a GET request. Mirrors are tried in order, the
+// first response with a 200 status code is returned
+func (m Mirrors) Get(ctx context.Context, client *http.Client, rawURL string) (*http.Response, error) {
+	return m.Do(ctx, client, rawURL, nil)
+}
+
+// Do is like Get but allows to modify each request (eg. to set headers)
+func (m Mirrors) Do(ctx context.Context, client *http.Client, rawURL string, prepare func(req *http.Request)) (*http.Response, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	var lastErr error
+	for _, u := range m.URLs(rawURL) {
+		req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+		if err != nil {
+			return nil, err
+		}
+		if prepare != nil {
+			prepare(req)
+		}
+		res, err := client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if res.StatusCode != http.StatusOK {
+			res.Body.Close()
+			lastErr = fmt.Errorf("invalid status code: %s from %s", res.Status, u)
+			continue
+		}
+		return res, nil
+	}
+	return nil, lastErr
+}
+
+// Client returns a http client that sends GET and HEAD requests to the mirrors of m (see `Transport`)
+func (m Mirrors) Client() *http.Client {
+	return &http.Client{Transport: &Transport{Mirrors: m}}
+}
+
+// credentialHeaders are removed from requests that are sent to a mirror on another host
+var credentialHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+
+// Transport is a `http.RoundTripper` that tries the mirrors of a host in order.
+// The original URL is used if all mirrors fail or respond with an error status code.
+// Requests other than GET and HEAD are never mirrored and mirrors on other hosts get no credentials
+type Transport struct {
+	Mirrors Mirrors
+	// Base is used to send the requests. `http.DefaultTransport` is used if this is nil
+	Base http.RoundTripper
+}
+
+// RoundTrip implements `http.RoundTripper`
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return base.RoundTrip(req)
+	}
+
+	urls := t.Mirrors.URLs(req.URL.String())
+	// the last entry is the original URL
+	for _, u := range urls[:len(urls)-1] {
+		parsed, err := url.Parse(u)
+		if err != nil {
+			continue
+		}
+		mirrored := req.Clone(req.Context())
+		mirrored.URL = parsed
+		mirrored.Host = parsed.Host
+		if parsed.Host != req.URL.Host {
+			// credentials are never sent to other hosts
+			for _, header := range credentialHeaders {
+				mirrored.Header.Del(header)
+			}
+		}
+		res, err := base.RoundTrip(mirrored)
+		if err != nil {
+			continue
+		}
+		if res.StatusCode >= 400 {
+			res.Body.Close()
+			continue
+		}
+		return res, nil
+	}
+	return base.RoundTrip(req)
+}
@@ -0,0 +1,94 @@
+package mirrors
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestMirrors_URLs(t *testing.T) {
+	m := Mirrors{
+		"libraries.minecraft.net": {"https://proxy.example.com/maven/", "https://other.example.com"},
+	}
+
+	tests := []struct {
+		name string
+		url  string
+		want []string
+	}{
+		{
+			"mirrored host",
+			"https://libraries.minecraft.net/org/lwjgl/lwjgl.jar",
+			[]string{
+				"https://proxy.example.com/maven/org/lwjgl/lwjgl.jar",
+				"https://other.example.com/org/lwjgl/lwjgl.jar",
+				"https://libraries.minecraft.net/org/lwjgl/lwjgl.jar",
+			},
+		},
+		{
+			"not mirrored",
+			"https://resources.download.minecraft.net/ab/abcd",
+			[]string{"https://resources.download.minecraft.net/ab/abcd"},
+		},
+		{
+			"no url",
+			"not a url",
+			[]string{"not a url"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.URLs(tt.url); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("URLs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransport(t *testing.T) {
+	original := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("original"))
+	}))
+	defer original.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer broken.Close()
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" || r.Header.Get("Cookie") != "" {
+			t.Error("credentials were sent to the mirror")
+		}
+		w.Write([]byte("mirror " + r.URL.Path))
+	}))
+	defer mirror.Close()
+
+	host := original.Listener.Addr().String()
+	tests := []struct {
+		name    string
+		mirrors Mirrors
+		method  string
+		want    string
+	}{
+		{"mirror is used", Mirrors{host: {broken.URL, mirror.URL}}, "GET", "mirror /v1/releases"},
+		{"falls back to original", Mirrors{host: {broken.URL}}, "GET", "original"},
+		{"post is not mirrored", Mirrors{host: {mirror.URL}}, "POST", "original"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, original.URL+"/v1/releases", nil)
+			req.Header.Set("Authorization", "Bearer secret")
+			req.Header.Set("Cookie", "session=secret")
+			res, err := tt.mirrors.Client().Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer res.Body.Close()
+			body, _ := ioutil.ReadAll(res.Body)
+			if string(body) != tt.want {
+				t.Errorf("got %q, want %q", body, tt.want)
+			}
+		})
+	}
+}
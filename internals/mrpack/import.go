@@ -0,0 +1,150 @@
+package mrpack
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/minepkg/minepkg/internals/pack"
+	"github.com/minepkg/minepkg/internals/utils"
+	"github.com/minepkg/minepkg/pkg/manifest"
+)
+
+// versionSuffix matches the version part of a jar name (eg. "-0.3.2+1.17.1")
+var versionSuffix = regexp.MustCompile(`[-_+][vV]?[0-9].*$`)
+
+// overridesDirs are extracted on the client. "server-overrides" are ignored
+var overridesDirs = []string{OverridesDir + "/", "client-overrides/"}
+
+// Pack is an opened .mrpack file
+type Pack struct {
+	Index *Index
+	zip   *zip.ReadCloser
+}
+
+// Open opens the .mrpack file at path and reads its index
+func Open(path string) (*Pack, error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range r.File {
+		if f.Name != IndexFile {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			r.Close()
+			return nil, err
+		}
+		defer rc.Close()
+
+		index := &Index{}
+		if err := json.NewDecoder(rc).Decode(index); err != nil {
+			r.Close()
+			return nil, fmt.Errorf("invalid %s: %w", IndexFile, err)
+		}
+		return &Pack{Index: index, zip: r}, nil
+	}
+
+	r.Close()
+	return nil, fmt.Errorf("%s does not contain a %s", path, IndexFile)
+}
+
+// Close closes the underlying zip file
+func (p *Pack) Close() error {
+	return p.zip.Close()
+}
+
+// ImportResult is the outcome of `Pack.Manifest`
+type ImportResult struct {
+	Manifest *manifest.Manifest
+	// Files are files outside of the mods directory (eg. resourcepacks).
+	// They can not be dependencies and have to be downloaded into the overwrites
+	Files []*File
+	// ServerOnly are files that are not supported on the client. They are skipped
+	ServerOnly []*File
+}
+
+// Manifest converts the index to a modpack manifest. Every mod becomes a https dependency
+// that is pinned by its sha1 hash
+func (p *Pack) Manifest() (*ImportResult, error) {
+	index := p.Index
+	man := manifest.New()
+	man.Package.Type = manifest.TypeModpack
+	man.Package.Name = utils.PackageName(index.Name, "imported-pack")
+	man.Package.Description = index.Summary
+	man.Package.Version = "0.1.0"
+
+	man.Requirements.Minecraft = index.Dependencies[DependencyMinecraft]
+	if man.Requirements.Minecraft == "" {
+		return nil, fmt.Errorf("%s has no %s dependency", IndexFile, DependencyMinecraft)
+	}
+	man.Requirements.FabricLoader = index.Dependencies[DependencyFabricLoader]
+	man.Requirements.ForgeLoader = index.Dependencies[DependencyForge]
+	man.Package.Platform = man.PlatformString()
+
+	result := &ImportResult{Manifest: man}
+	for _, file := range index.Files {
+		switch {
+		case file.Env != nil && file.Env.Client == EnvUnsupported:
+			result.ServerOnly = append(result.ServerOnly, file)
+		case len(file.Downloads) == 0 || file.Hashes["sha1"] == "":
+			return nil, fmt.Errorf("%s has no download url or sha1 hash", file.Path)
+		case path.Dir(file.Path) == "mods" && path.Ext(file.Path) == ".jar":
+			name := DependencyName(file.Path)
+			for n := 2; man.Dependencies[name] != ""; n++ {
+				name = fmt.Sprintf("%s-%d", DependencyName(file.Path), n)
+			}
+			man.AddDependency(name, file.Downloads[0]+"#sha1="+file.Hashes["sha1"])
+		default:
+			result.Files = append(result.Files, file)
+		}
+	}
+
+	return result, nil
+}
+
+// DependencyName returns a dependency name for the given jar path
+// (eg. "mods/sodium-fabric-mc1.17.1-0.3.2.jar" → "sodium-fabric-mc1-17-1")
+func DependencyName(jarPath string) string {
+	name := strings.TrimSuffix(path.Base(jarPath), path.Ext(jarPath))
+	if trimmed := versionSuffix.ReplaceAllString(name, ""); trimmed != "" {
+		name = trimmed
+	}
+	return utils.PackageName(name, "mod")
+}
+
+// ExtractOverrides extracts the overrides (and client overrides) to dest.
+// It returns the number of extracted files
+func (p *Pack) ExtractOverrides(dest string) (int, error) {
+	count := 0
+	for _, f := range p.zip.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		var name string
+		for _, prefix := range overridesDirs {
+			if strings.HasPrefix(f.Name, prefix) {
+				name = strings.TrimPrefix(f.Name, prefix)
+			}
+		}
+		if name == "" {
+			continue
+		}
+		if err := pack.SanitizeExtractPath(name, dest); err != nil {
+			return count, err
+		}
+		if err := pack.ExtractFile(f, filepath.Join(dest, name)); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
@@ -0,0 +1,199 @@
+// Package mrpack reads and writes Modrinth modpacks (.mrpack files)
+package mrpack
+
+import (
+	"crypto/sha1"
+	"crypto/sha512"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/minepkg/minepkg/internals/bundle"
+	"github.com/minepkg/minepkg/internals/fabric"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/minepkg/minepkg/pkg/manifest"
+)
+
+const (
+	// IndexFile is the name of the index inside the pack
+	IndexFile = "modrinth.index.json"
+	// OverridesDir contains files that are copied into the minecraft directory
+	OverridesDir = "overrides"
+
+	// DependencyMinecraft is the `dependencies` key of the Minecraft version
+	DependencyMinecraft = "minecraft"
+	// DependencyFabricLoader is the `dependencies` key of the Fabric loader version
+	DependencyFabricLoader = "fabric-loader"
+	// DependencyForge is the `dependencies` key of the Forge version
+	DependencyForge = "forge"
+
+	// EnvRequired marks a file as required on a side
+	EnvRequired = "required"
+	// EnvOptional marks a file as optional on a side
+	EnvOptional = "optional"
+	// EnvUnsupported marks a file as not usable on a side
+	EnvUnsupported = "unsupported"
+)
+
+// AllowedHosts are the hosts Modrinth accepts in the `downloads` of packs that are uploaded to Modrinth
+var AllowedHosts = map[string]bool{
+	"cdn.modrinth.com":          true,
+	"github.com":                true,
+	"raw.githubusercontent.com": true,
+	"gitlab.com":                true,
+}
+
+// Allowed returns true if Modrinth accepts rawURL as a download
+func Allowed(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	return err == nil && parsed.Scheme == "https" && AllowedHosts[parsed.Host]
+}
+
+// Index is the content of the `modrinth.index.json`
+type Index struct {
+	FormatVersion int               `json:"formatVersion"`
+	Game          string            `json:"game"`
+	VersionID     string            `json:"versionId"`
+	Name          string            `json:"name"`
+	Summary       string            `json:"summary,omitempty"`
+	Files         []*File           `json:"files"`
+	Dependencies  map[string]string `json:"dependencies"`
+}
+
+// File is a single file that has to be downloaded
+type File struct {
+	// Path is the destination relative to the minecraft directory
+	Path      string            `json:"path"`
+	Hashes    map[string]string `json:"hashes"`
+	Env       *Env              `json:"env,omitempty"`
+	Downloads []string          `json:"downloads"`
+	FileSize  int64             `json:"fileSize"`
+}
+
+// Env describes on which side a file is needed
+type Env struct {
+	Client string `json:"client"`
+	Server string `json:"server"`
+}
+
+// Export writes the prepared instance as a Modrinth modpack.
+// Locked mods are referenced by their download url, the overwrites are placed under `overrides/`.
+// Modrinth rejects downloads from other hosts than the `AllowedHosts` (including the minepkg API),
+// so these mods are placed under `overrides/` as well. Data packs are placed in the worlds they are linked to
+func Export(w io.Writer, instance *instances.Instance) error {
+	man := instance.Manifest
+	index := &Index{
+		FormatVersion: 1,
+		Game:          "minecraft",
+		VersionID:     man.Package.Version,
+		Name:          man.Package.Name,
+		Summary:       man.Package.Description,
+		Files:         make([]*File, 0, len(instance.Lockfile.Dependencies)),
+		Dependencies:  Dependencies(instance.Lockfile),
+	}
+
+	b := bundle.NewWriter(w, instance.CacheDir)
+	for _, dep := range instance.Lockfile.Dependencies {
+		if dep.URL == "" {
+			continue
+		}
+		paths, err := instance.InstallPaths(dep)
+		if err != nil {
+			return err
+		}
+		src := filepath.Join(instance.PackageCacheDir(), dep.Name, dep.Version+dep.FileExt())
+		for _, path := range paths {
+			if !Allowed(dep.URL) {
+				if err := b.AddFile(OverridesDir+"/"+path, src); err != nil {
+					return err
+				}
+				continue
+			}
+			file, err := NewFile(src, path, dep.URL)
+			if err != nil {
+				return err
+			}
+			index.Files = append(index.Files, file)
+		}
+	}
+	sort.Slice(index.Files, func(a, b int) bool { return index.Files[a].Path < index.Files[b].Path })
+
+	buf, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := b.AddBytes(IndexFile, buf); err != nil {
+		return err
+	}
+	if err := b.AddMinecraftFiles(OverridesDir, instance); err != nil {
+		return err
+	}
+	return b.Close()
+}
+
+// Dependencies returns the `dependencies` block for the given lockfile
+func Dependencies(lockfile *manifest.Lockfile) map[string]string {
+	deps := map[string]string{
+		DependencyMinecraft: lockfile.MinecraftVersion(),
+	}
+	switch {
+	case lockfile.Fabric != nil:
+		deps[DependencyFabricLoader] = lockfile.Fabric.FabricLoader
+	case lockfile.Forge != nil:
+		deps[DependencyForge] = lockfile.Forge.ForgeLoader
+	}
+	return deps
+}
+
+// NewFile hashes the local file src and returns its index entry
+func NewFile(src string, path string, url string) (*File, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sha1Hash := sha1.New()
+	sha512Hash := sha512.New()
+	size, err := io.Copy(io.MultiWriter(sha1Hash, sha512Hash), f)
+	if err != nil {
+		return nil, err
+	}
+
+	return &File{
+		Path: path,
+		Hashes: map[string]string{
+			"sha1":   hexSum(sha1Hash),
+			"sha512": hexSum(sha512Hash),
+		},
+		Env:       JarEnv(src),
+		Downloads: []string{url},
+		FileSize:  size,
+	}, nil
+}
+
+// JarEnv returns the sides a mod jar is needed on, based on the "environment" in its fabric.mod.json.
+// Jars without a fabric.mod.json are required on both sides
+func JarEnv(jar string) *Env {
+	env := &Env{Client: EnvRequired, Server: EnvRequired}
+	fabricMan, err := fabric.ReadJarManifest(jar)
+	if err != nil {
+		return env
+	}
+	switch fabricMan.Environment {
+	case "client":
+		env.Server = EnvUnsupported
+	case "server":
+		env.Client = EnvUnsupported
+	}
+	return env
+}
+
+func hexSum(h hash.Hash) string {
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
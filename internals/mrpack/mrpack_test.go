@@ -0,0 +1,184 @@
+package mrpack
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/minepkg/minepkg/pkg/manifest"
+)
+
+func TestExport(t *testing.T) {
+	instance := &instances.Instance{
+		Directory: t.TempDir(),
+		CacheDir:  t.TempDir(),
+		Manifest:  manifest.New(),
+		Lockfile:  manifest.NewLockfile(),
+	}
+	instance.Manifest.Package.Name = "test-pack"
+	instance.Manifest.Package.Version = "1.0.0"
+	instance.Lockfile.Fabric = &manifest.FabricLock{Minecraft: "1.17.1", FabricLoader: "0.11.6"}
+	instance.Lockfile.AddDependency(&manifest.DependencyLock{
+		Name:    "client-mod",
+		Version: "1.0.0",
+		Type:    manifest.DependencyLockTypeMod,
+		URL:     "https://cdn.modrinth.com/data/AAAAAAAA/versions/1.0.0/client-mod.jar",
+	})
+	instance.Lockfile.AddDependency(&manifest.DependencyLock{
+		Name:    "minepkg-mod",
+		Version: "1.0.0",
+		Type:    manifest.DependencyLockTypeMod,
+		URL:     "https://api.preview.minepkg.io/v1/releases/fabric/minepkg-mod@1.0.0/download",
+	})
+
+	// a jar with a client only fabric.mod.json
+	jar := new(bytes.Buffer)
+	zw := zip.NewWriter(jar)
+	w, _ := zw.Create("fabric.mod.json")
+	w.Write([]byte(`{"id": "client-mod", "environment": "client"}`))
+	zw.Close()
+	jarPath := filepath.Join(instance.PackageCacheDir(), "client-mod", "1.0.0.jar")
+	os.MkdirAll(filepath.Dir(jarPath), os.ModePerm)
+	if err := ioutil.WriteFile(jarPath, jar.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	minepkgJarPath := filepath.Join(instance.PackageCacheDir(), "minepkg-mod", "1.0.0.jar")
+	os.MkdirAll(filepath.Dir(minepkgJarPath), os.ModePerm)
+	ioutil.WriteFile(minepkgJarPath, jar.Bytes(), 0644)
+
+	// a data pack is placed in the existing world
+	instance.Lockfile.AddDependency(&manifest.DependencyLock{
+		Name:    "test-data",
+		Version: "1.0.0",
+		Type:    manifest.DependencyLockTypeDatapack,
+		URL:     "https://cdn.modrinth.com/data/BBBBBBBB/versions/1.0.0/test-data.zip",
+	})
+	dataPath := filepath.Join(instance.PackageCacheDir(), "test-data", "1.0.0.zip")
+	os.MkdirAll(filepath.Dir(dataPath), os.ModePerm)
+	ioutil.WriteFile(dataPath, []byte("zip"), 0644)
+	os.MkdirAll(filepath.Join(instance.McDir(), "saves", "world"), os.ModePerm)
+
+	os.MkdirAll(filepath.Join(instance.OverwritesDir(), "config"), os.ModePerm)
+	ioutil.WriteFile(filepath.Join(instance.OverwritesDir(), "config", "test.json"), []byte("{}"), 0644)
+	os.MkdirAll(instance.McDir(), os.ModePerm)
+	if err := instance.CopyOverwrites(); err != nil {
+		t.Fatal(err)
+	}
+
+	out := new(bytes.Buffer)
+	if err := Export(out, instance); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]*zip.File)
+	for _, f := range r.File {
+		files[f.Name] = f
+	}
+	if files["overrides/config/test.json"] == nil {
+		t.Error("overwrites are missing in the overrides")
+	}
+	if files["overrides/mods/minepkg-mod-1.0.0.jar"] == nil {
+		t.Error("mod that is not hosted on an allowed host is missing in the overrides")
+	}
+
+	rc, err := files[IndexFile].Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	index := &Index{}
+	if err := json.NewDecoder(rc).Decode(index); err != nil {
+		t.Fatal(err)
+	}
+
+	if index.Dependencies[DependencyMinecraft] != "1.17.1" || index.Dependencies[DependencyFabricLoader] != "0.11.6" {
+		t.Errorf("unexpected dependencies %v", index.Dependencies)
+	}
+	if len(index.Files) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(index.Files))
+	}
+	if path := index.Files[1].Path; path != "saves/world/datapacks/test-data.zip" {
+		t.Errorf("expected the data pack in the world, got %s", path)
+	}
+	file := index.Files[0]
+	if file.Path != "mods/client-mod-1.0.0.jar" || file.Downloads[0] != "https://cdn.modrinth.com/data/AAAAAAAA/versions/1.0.0/client-mod.jar" {
+		t.Errorf("unexpected file %+v", file)
+	}
+	if file.Env.Client != EnvRequired || file.Env.Server != EnvUnsupported {
+		t.Errorf("unexpected env %+v", file.Env)
+	}
+	if file.Hashes["sha1"] == "" || file.Hashes["sha512"] == "" || file.FileSize != int64(jar.Len()) {
+		t.Errorf("unexpected hashes or size %+v", file)
+	}
+}
+
+func TestImport(t *testing.T) {
+	index := &Index{
+		FormatVersion: 1,
+		Game:          "minecraft",
+		Name:          "Some Pack",
+		Dependencies:  map[string]string{DependencyMinecraft: "1.17.1", DependencyFabricLoader: "0.11.6"},
+		Files: []*File{
+			{Path: "mods/fabric-api-0.40.1+1.17.jar", Hashes: map[string]string{"sha1": "abc"}, Downloads: []string{"https://example.com/fabric-api.jar"}},
+			{Path: "mods/server-only-1.0.jar", Hashes: map[string]string{"sha1": "def"}, Downloads: []string{"https://example.com/server.jar"}, Env: &Env{Client: EnvUnsupported, Server: EnvRequired}},
+			{Path: "resourcepacks/pack.zip", Hashes: map[string]string{"sha1": "123"}, Downloads: []string{"https://example.com/pack.zip"}},
+		},
+	}
+
+	packPath := filepath.Join(t.TempDir(), "test.mrpack")
+	f, err := os.Create(packPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	w, _ := zw.Create(IndexFile)
+	json.NewEncoder(w).Encode(index)
+	w, _ = zw.Create("overrides/config/test.json")
+	w.Write([]byte("{}"))
+	w, _ = zw.Create("server-overrides/server.properties")
+	w.Write([]byte("motd=test"))
+	zw.Close()
+	f.Close()
+
+	mp, err := Open(packPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mp.Close()
+
+	result, err := mp.Manifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	man := result.Manifest
+	if man.Package.Name != "some-pack" || man.Package.Platform != "fabric" || man.Requirements.FabricLoader != "0.11.6" {
+		t.Errorf("unexpected manifest %+v", man.Package)
+	}
+	if man.Dependencies["fabric-api"] != "https://example.com/fabric-api.jar#sha1=abc" || len(man.Dependencies) != 1 {
+		t.Errorf("unexpected dependencies %v", man.Dependencies)
+	}
+	if len(result.Files) != 1 || len(result.ServerOnly) != 1 {
+		t.Errorf("unexpected files %v / server only files %v", result.Files, result.ServerOnly)
+	}
+
+	dest := t.TempDir()
+	count, err := mp.ExtractOverrides(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 extracted file, got %d", count)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "config", "test.json")); err != nil {
+		t.Error("overrides were not extracted")
+	}
+}
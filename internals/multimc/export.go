@@ -0,0 +1,99 @@
+package multimc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/minepkg/minepkg/internals/bundle"
+	"github.com/minepkg/minepkg/internals/instances"
+	"github.com/minepkg/minepkg/pkg/manifest"
+)
+
+// String returns the config in the `instance.cfg` format
+func (c Config) String() string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString("[General]\n")
+	for _, key := range keys {
+		fmt.Fprintf(&b, "%s=%s\n", key, c[key])
+	}
+	return b.String()
+}
+
+// NewPack returns the components needed for the given lockfile
+func NewPack(lockfile *manifest.Lockfile) *Pack {
+	pack := &Pack{FormatVersion: 1}
+	pack.Components = append(pack.Components, &Component{
+		UID:       UIDMinecraft,
+		Version:   lockfile.MinecraftVersion(),
+		Important: true,
+	})
+
+	switch {
+	case lockfile.Fabric != nil:
+		pack.Components = append(
+			pack.Components,
+			&Component{UID: UIDIntermediary, Version: lockfile.Fabric.Minecraft},
+			&Component{UID: UIDFabricLoader, Version: lockfile.Fabric.FabricLoader},
+		)
+	case lockfile.Forge != nil:
+		pack.Components = append(pack.Components, &Component{UID: UIDForge, Version: lockfile.Forge.ForgeLoader})
+	}
+	return pack
+}
+
+// Export writes the prepared instance as a zip that can be imported by MultiMC and Prism Launcher.
+// It contains the `instance.cfg`, `mmc-pack.json`, every locked mod and the overwrites.
+// Data packs are placed in the worlds they are linked to
+func Export(w io.Writer, instance *instances.Instance) error {
+	name := instance.Manifest.Package.Name
+	b := bundle.NewWriter(w, instance.CacheDir)
+
+	config := Config{
+		"InstanceType": "OneSix",
+		"name":         name,
+	}
+	if err := b.AddBytes(name+"/instance.cfg", []byte(config.String())); err != nil {
+		return err
+	}
+
+	pack, err := json.MarshalIndent(NewPack(instance.Lockfile), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := b.AddBytes(name+"/mmc-pack.json", pack); err != nil {
+		return err
+	}
+
+	mcDir := name + "/.minecraft"
+	for _, dep := range instance.Lockfile.Dependencies {
+		if dep.URL == "" {
+			continue
+		}
+		paths, err := instance.InstallPaths(dep)
+		if err != nil {
+			return err
+		}
+		src := filepath.Join(instance.PackageCacheDir(), dep.Name, dep.Version+dep.FileExt())
+		for _, path := range paths {
+			if err := b.AddFile(mcDir+"/"+path, src); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := b.AddMinecraftFiles(mcDir, instance); err != nil {
+		return err
+	}
+
+	return b.Close()
+}
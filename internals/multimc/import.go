@@ -0,0 +1,107 @@
+package multimc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/minepkg/minepkg/internals/api"
+	"github.com/minepkg/minepkg/internals/utils"
+	"github.com/minepkg/minepkg/pkg/manifest"
+)
+
+// ReleaseFinder returns the release of a mod jar.
+// It should return `api.ErrNotFound` if there is no matching release
+type ReleaseFinder func(ctx context.Context, jar string) (*api.Release, error)
+
+// ImportResult is the outcome of `Instance.Import`
+type ImportResult struct {
+	Manifest *manifest.Manifest
+	// Matched maps jar filenames to the release they were matched with
+	Matched map[string]*api.Release
+	// Unmatched are jar filenames without a matching release. They were copied to the overwrites
+	Unmatched []string
+}
+
+// Import converts the instance into a minepkg modpack in targetDir.
+// Every mod jar is looked up with find. Matches become dependencies,
+// unmatched jars and the config directory are copied to the overwrites.
+// The manifest is returned but not written
+func (i *Instance) Import(ctx context.Context, find ReleaseFinder, targetDir string) (*ImportResult, error) {
+	mcVersion := i.Pack.Version(UIDMinecraft)
+	if mcVersion == "" {
+		return nil, fmt.Errorf("mmc-pack.json has no %s component", UIDMinecraft)
+	}
+
+	man := manifest.New()
+	man.Package.Type = manifest.TypeModpack
+	man.Package.Name = utils.PackageName(i.Config.Name(), "imported-pack")
+	man.Package.Version = "0.1.0"
+	man.Package.Platform = i.Platform()
+	man.Requirements.Minecraft = mcVersion
+	man.Requirements.FabricLoader = i.Pack.Version(UIDFabricLoader)
+	man.Requirements.ForgeLoader = i.Pack.Version(UIDForge)
+
+	result := &ImportResult{
+		Manifest: man,
+		Matched:  make(map[string]*api.Release),
+	}
+
+	jars, err := filepath.Glob(filepath.Join(i.ModsDir(), "*.jar"))
+	if err != nil {
+		return nil, err
+	}
+	overwrites := filepath.Join(targetDir, "overwrites")
+	for _, jar := range jars {
+		release, err := find(ctx, jar)
+		switch {
+		case err == nil:
+			man.AddDependency(release.Package.Name, release.Package.Version)
+			result.Matched[filepath.Base(jar)] = release
+			continue
+		case !errors.Is(err, api.ErrNotFound):
+			return nil, err
+		}
+
+		result.Unmatched = append(result.Unmatched, filepath.Base(jar))
+		if err := copyFile(jar, filepath.Join(overwrites, "mods", filepath.Base(jar))); err != nil {
+			return nil, err
+		}
+	}
+
+	configDir := filepath.Join(i.McDir(), "config")
+	if _, err := os.Stat(configDir); err == nil {
+		if err := copyDir(configDir, filepath.Join(overwrites, "config")); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func copyFile(src string, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), os.ModePerm); err != nil {
+		return err
+	}
+	content, err := ioutil.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(dest, content, 0644)
+}
+
+func copyDir(src string, dest string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		return copyFile(path, filepath.Join(dest, rel))
+	})
+}
@@ -0,0 +1,143 @@
+// Package multimc reads MultiMC and Prism Launcher instances
+package multimc
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// UIDMinecraft is the component uid of Minecraft
+	UIDMinecraft = "net.minecraft"
+	// UIDFabricLoader is the component uid of the Fabric loader
+	UIDFabricLoader = "net.fabricmc.fabric-loader"
+	// UIDIntermediary is the component uid of the Fabric intermediary mappings
+	UIDIntermediary = "net.fabricmc.intermediary"
+	// UIDForge is the component uid of Forge
+	UIDForge = "net.minecraftforge"
+)
+
+// ErrNoInstance is returned when a directory does not contain a MultiMC instance
+var ErrNoInstance = errors.New("directory does not contain an instance.cfg or mmc-pack.json")
+
+// Config is the content of an `instance.cfg` file
+type Config map[string]string
+
+// Name returns the name of the instance
+func (c Config) Name() string {
+	return c["name"]
+}
+
+// ParseConfig parses an `instance.cfg` file. Sections (like "[General]") are ignored
+func ParseConfig(r io.Reader) (Config, error) {
+	config := make(Config)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "[") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		config[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return config, scanner.Err()
+}
+
+// Component is a single component (Minecraft, a mod loader, …) of an instance
+type Component struct {
+	UID       string `json:"uid"`
+	Version   string `json:"version"`
+	Important bool   `json:"important,omitempty"`
+}
+
+// Pack is the content of an `mmc-pack.json` file
+type Pack struct {
+	Components    []*Component `json:"components"`
+	FormatVersion int          `json:"formatVersion"`
+}
+
+// Version returns the version of the component with the given uid or an empty string
+func (p *Pack) Version(uid string) string {
+	for _, c := range p.Components {
+		if c.UID == uid {
+			return c.Version
+		}
+	}
+	return ""
+}
+
+// Instance is a MultiMC or Prism Launcher instance on disk
+type Instance struct {
+	// Directory is the instance directory that contains the `instance.cfg`
+	Directory string
+	Config    Config
+	Pack      *Pack
+}
+
+// Open reads the instance in dir
+func Open(dir string) (*Instance, error) {
+	instance := &Instance{Directory: dir}
+
+	cfg, err := os.Open(filepath.Join(dir, "instance.cfg"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNoInstance
+		}
+		return nil, err
+	}
+	defer cfg.Close()
+	if instance.Config, err = ParseConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	pack, err := os.Open(filepath.Join(dir, "mmc-pack.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNoInstance
+		}
+		return nil, err
+	}
+	defer pack.Close()
+	instance.Pack = &Pack{}
+	if err := json.NewDecoder(pack).Decode(instance.Pack); err != nil {
+		return nil, err
+	}
+
+	return instance, nil
+}
+
+// McDir returns the minecraft directory of the instance.
+// MultiMC uses ".minecraft", newer Prism versions use "minecraft"
+func (i *Instance) McDir() string {
+	for _, name := range []string{".minecraft", "minecraft"} {
+		dir := filepath.Join(i.Directory, name)
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+	return filepath.Join(i.Directory, ".minecraft")
+}
+
+// ModsDir returns the mods directory of the instance
+func (i *Instance) ModsDir() string {
+	return filepath.Join(i.McDir(), "mods")
+}
+
+// Platform returns "fabric", "forge" or "vanilla" depending on the installed components
+func (i *Instance) Platform() string {
+	switch {
+	case i.Pack.Version(UIDFabricLoader) != "":
+		return "fabric"
+	case i.Pack.Version(UIDForge) != "":
+		return "forge"
+	}
+	return "vanilla"
+}
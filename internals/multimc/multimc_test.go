@@ -0,0 +1,98 @@
+package multimc
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/minepkg/minepkg/internals/api"
+	"github.com/minepkg/minepkg/pkg/manifest"
+)
+
+func writeTestFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestImport(t *testing.T) {
+	dir := t.TempDir()
+	target := t.TempDir()
+
+	writeTestFile(t, filepath.Join(dir, "instance.cfg"), "[General]\nInstanceType=OneSix\nname=My Cool Pack!\n")
+	writeTestFile(t, filepath.Join(dir, "mmc-pack.json"), `{
+		"components": [
+			{"uid": "net.minecraft", "version": "1.17.1", "important": true},
+			{"uid": "net.fabricmc.intermediary", "version": "1.17.1"},
+			{"uid": "net.fabricmc.fabric-loader", "version": "0.11.6"}
+		],
+		"formatVersion": 1
+	}`)
+	writeTestFile(t, filepath.Join(dir, "minecraft", "mods", "known.jar"), "known")
+	writeTestFile(t, filepath.Join(dir, "minecraft", "mods", "unknown.jar"), "unknown")
+	writeTestFile(t, filepath.Join(dir, "minecraft", "config", "some.json"), "{}")
+
+	instance, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	find := func(ctx context.Context, jar string) (*api.Release, error) {
+		if filepath.Base(jar) != "known.jar" {
+			return nil, api.ErrNotFound
+		}
+		man := manifest.New()
+		man.Package.Name = "known"
+		man.Package.Version = "1.0.0"
+		return &api.Release{Manifest: man}, nil
+	}
+
+	result, err := instance.Import(context.Background(), find, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	man := result.Manifest
+	if man.Package.Name != "my-cool-pack" {
+		t.Errorf("unexpected name %s", man.Package.Name)
+	}
+	if man.Requirements.Minecraft != "1.17.1" || man.Requirements.FabricLoader != "0.11.6" || man.Package.Platform != "fabric" {
+		t.Errorf("unexpected requirements %+v", man.Requirements)
+	}
+	if man.Dependencies["known"] != "1.0.0" || len(man.Dependencies) != 1 {
+		t.Errorf("unexpected dependencies %v", man.Dependencies)
+	}
+	if len(result.Unmatched) != 1 || result.Unmatched[0] != "unknown.jar" {
+		t.Errorf("unexpected unmatched jars %v", result.Unmatched)
+	}
+	for _, file := range []string{"mods/unknown.jar", "config/some.json"} {
+		if _, err := os.Stat(filepath.Join(target, "overwrites", file)); err != nil {
+			t.Errorf("%s was not copied to the overwrites", file)
+		}
+	}
+}
+
+func TestNewPack(t *testing.T) {
+	lockfile := manifest.NewLockfile()
+	lockfile.Fabric = &manifest.FabricLock{Minecraft: "1.17.1", FabricLoader: "0.11.6", Mapping: "1.17.1+build.1"}
+
+	pack := NewPack(lockfile)
+	if pack.Version(UIDMinecraft) != "1.17.1" || pack.Version(UIDIntermediary) != "1.17.1" || pack.Version(UIDFabricLoader) != "0.11.6" {
+		t.Errorf("unexpected components %+v", pack.Components)
+	}
+
+	config, err := ParseConfig(strings.NewReader(Config{"name": "test", "InstanceType": "OneSix"}.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Name() != "test" || config["InstanceType"] != "OneSix" {
+		t.Errorf("config did not survive a round trip: %v", config)
+	}
+}
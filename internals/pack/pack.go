@@ -2,10 +2,12 @@ package pack
 
 import (
 	"archive/zip"
+	"crypto/sha256"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -47,9 +49,57 @@ func (p *Reader) Files() []*zip.File {
 	return p.zipReader.File
 }
 
+// ReadFile returns the content of the contained file with the given name
+func (p *Reader) ReadFile(name string) ([]byte, error) {
+	for _, f := range p.Files() {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return ioutil.ReadAll(rc)
+	}
+	return nil, os.ErrNotExist
+}
+
+// FileHashes returns the sha256 hashes of all contained files by their path. Directories are skipped.
+// An error is returned if a path is absolute or leaves the root of the zip (see `checkPath`)
+func (p *Reader) FileHashes() (map[string]string, error) {
+	hashes := make(map[string]string)
+	for _, f := range p.Files() {
+		if err := checkPath(f.Name); err != nil {
+			return nil, err
+		}
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		h := sha256.New()
+		_, err = io.Copy(h, rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		hashes[f.Name] = fmt.Sprintf("%x", h.Sum(nil))
+	}
+	return hashes, nil
+}
+
 // ExtractModpack will extract everything in this zipfile to `dest` but will
 // NOT overwrite existing savefiles
 func (p *Reader) ExtractModpack(dest string) error {
+	return p.ExtractModpackExcept(dest, nil)
+}
+
+// ExtractModpackExcept works like `ExtractModpack` but does not extract the files in skip
+// (zip paths like "config/foo.json")
+func (p *Reader) ExtractModpackExcept(dest string, skip map[string]bool) error {
 	zipReader := p.zipReader
 
 	skipPrefixes := []string{}
@@ -59,7 +109,7 @@ outer:
 	for _, f := range zipReader.File {
 
 		// make sure zip only contains valid paths
-		if err := sanitizeExtractPath(f.Name, dest); err != nil {
+		if err := SanitizeExtractPath(f.Name, dest); err != nil {
 			return err
 		}
 
@@ -77,7 +127,7 @@ outer:
 		}
 
 		// not sure if this is optimal...
-		if f.FileInfo().IsDir() {
+		if f.FileInfo().IsDir() || skip[f.Name] {
 			continue outer
 		}
 
@@ -162,8 +212,9 @@ func Open(filePath string) (*PackageFile, error) {
 	}, nil
 }
 
+// SanitizeExtractPath returns an error if filePath would be extracted outside of destination
 // stolen from https://github.com/mholt/archiver/v3/blob/e4ef56d48eb029648b0e895bb0b6a393ef0829c3/archiver.go#L110-L119
-func sanitizeExtractPath(filePath string, destination string) error {
+func SanitizeExtractPath(filePath string, destination string) error {
 	// to avoid zip slip (writing outside of the destination), we resolve
 	// the target path, and make sure it's nested in the intended
 	// destination, or bail otherwise.
@@ -173,3 +224,64 @@ func sanitizeExtractPath(filePath string, destination string) error {
 	}
 	return nil
 }
+
+// WriteZip archives the files (paths relative to dir) into a zip that is written to w
+func WriteZip(w io.Writer, dir string, files []string) error {
+	archive := zip.NewWriter(w)
+	for _, file := range files {
+		if err := addToZip(archive, filepath.Join(dir, file), filepath.ToSlash(file)); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+func addToZip(archive *zip.Writer, src string, name string) error {
+	source, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	target, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(target, source)
+	return err
+}
+
+// ExtractFile writes the zip file f to target and creates the missing parent directories.
+// The file mode of f is kept. Use `SanitizeExtractPath` first if target is based on the name of f
+func ExtractFile(f *zip.File, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+		return err
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	mode := f.Mode().Perm()
+	if mode == 0 {
+		mode = 0644
+	}
+	dest, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	defer dest.Close()
+	_, err = io.Copy(dest, rc)
+	return err
+}
+
+// checkPath returns an error if the zip path is absolute, uses backslashes or leaves the root of the zip with ".."
+func checkPath(name string) error {
+	clean := path.Clean(name)
+	if path.IsAbs(name) || filepath.IsAbs(name) || filepath.VolumeName(name) != "" ||
+		strings.Contains(name, "\\") || clean == ".." || strings.HasPrefix(clean, "../") {
+		return fmt.Errorf("%s: illegal file path", name)
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package pack
 
 import (
+	"archive/zip"
+	"bytes"
 	"os"
 	"testing"
 )
@@ -50,3 +52,21 @@ func TestPackage_Manifest(t *testing.T) {
 		})
 	}
 }
+
+func TestFileHashesIllegalPath(t *testing.T) {
+	names := []string{"../evil.txt", "config/../../evil.txt", "/etc/evil.txt", "..\\evil.txt"}
+	for _, name := range names {
+		buf := new(bytes.Buffer)
+		zw := zip.NewWriter(buf)
+		w, _ := zw.Create("config/ok.txt")
+		w.Write([]byte("ok"))
+		w, _ = zw.Create(name)
+		w.Write([]byte("evil"))
+		zw.Close()
+
+		p := NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		if _, err := p.FileHashes(); err == nil {
+			t.Errorf("expected an error for %s", name)
+		}
+	}
+}
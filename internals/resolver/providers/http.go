@@ -17,6 +17,8 @@ type HttpProvider struct {
 
 type httpResult struct {
 	cacheKey   string
+	url        string
+	sha1       string
 	dependency *manifest.InterpretedDependency
 }
 
@@ -25,8 +27,9 @@ func (h *httpResult) Lock() *manifest.DependencyLock {
 		Name:     h.dependency.Name,
 		Provider: h.dependency.Provider,
 		Type:     "mod",
-		URL:      h.dependency.Source,
+		URL:      h.url,
 		Version:  h.cacheKey,
+		Sha1:     h.sha1,
 	}
 
 	return lock
@@ -37,7 +40,22 @@ func (h *httpResult) Dependencies() []*manifest.InterpretedDependency {
 	return []*manifest.InterpretedDependency{}
 }
 
+// PinnedSource returns the url without the fragment and the sha1 hash if the source
+// is pinned by hash (eg. "https://example.com/mod.jar#sha1=8f3c…")
+func PinnedSource(source string) (string, string) {
+	parts := strings.SplitN(source, "#", 2)
+	if len(parts) == 2 && strings.HasPrefix(parts[1], "sha1=") {
+		return parts[0], strings.TrimPrefix(parts[1], "sha1=")
+	}
+	return source, ""
+}
+
 func (h *HttpProvider) Resolve(ctx context.Context, request *Request) (Result, error) {
+	// pinned urls do not change, so no need to ask the server
+	if url, sha1 := PinnedSource(request.Dependency.Source); sha1 != "" {
+		return &httpResult{dependency: request.Dependency, cacheKey: sha1, url: url, sha1: sha1}, nil
+	}
+
 	req, err := http.NewRequest("HEAD", request.Dependency.Source, nil)
 	if err != nil {
 		return nil, err
@@ -64,7 +82,7 @@ func (h *HttpProvider) Resolve(ctx context.Context, request *Request) (Result, e
 		)
 	}
 
-	return &httpResult{dependency: request.Dependency, cacheKey: cacheKey}, nil
+	return &httpResult{dependency: request.Dependency, cacheKey: cacheKey, url: request.Dependency.Source}, nil
 }
 
 func (h *HttpProvider) Fetch(ctx context.Context, toFetch Result) (io.Reader, int, error) {
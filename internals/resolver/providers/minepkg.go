@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/minepkg/minepkg/internals/api"
+	"github.com/minepkg/minepkg/internals/mirrors"
 	"github.com/minepkg/minepkg/pkg/manifest"
 )
 
@@ -54,12 +55,7 @@ func (m *MinepkgProvider) Resolve(ctx context.Context, request *Request) (Result
 }
 
 func (m *MinepkgProvider) Fetch(ctx context.Context, toFetch Result) (io.Reader, int, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", toFetch.Lock().URL, nil)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	fileRes, err := http.DefaultClient.Do(req)
+	fileRes, err := mirrors.Default.Get(ctx, http.DefaultClient, toFetch.Lock().URL)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -0,0 +1,49 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/minepkg/minepkg/pkg/manifest"
+)
+
+// WorkspaceProvider resolves members of the local workspace from source instead of the registry
+type WorkspaceProvider struct {
+	// Members maps package names to the manifest of the member
+	Members map[string]*manifest.Manifest
+}
+
+type workspaceResult struct {
+	name     string
+	manifest *manifest.Manifest
+}
+
+func (w *workspaceResult) Lock() *manifest.DependencyLock {
+	version := w.manifest.Package.Version
+	if version == "" {
+		version = "local"
+	}
+	return &manifest.DependencyLock{
+		Name:     w.name,
+		Version:  version,
+		Type:     w.manifest.Package.Type,
+		Provider: "workspace",
+	}
+}
+
+func (w *workspaceResult) Dependencies() []*manifest.InterpretedDependency {
+	return w.manifest.InterpretedDependencies()
+}
+
+func (w *WorkspaceProvider) Resolve(ctx context.Context, request *Request) (Result, error) {
+	man, ok := w.Members[request.Dependency.Name]
+	if !ok {
+		return nil, fmt.Errorf("%s is not a member of this workspace", request.Dependency.Name)
+	}
+	return &workspaceResult{name: request.Dependency.Name, manifest: man}, nil
+}
+
+func (w *WorkspaceProvider) Fetch(ctx context.Context, toFetch Result) (io.Reader, int, error) {
+	return nil, 0, fmt.Errorf("workspace members are built locally and can not be fetched")
+}
@@ -65,6 +65,9 @@ type Resolver struct {
 	downloadWg        sync.WaitGroup
 	subscribers       []chan *Resolved
 	Providers         map[string]providers.Provider
+	// Replace maps package names to a provider that is used instead of the one in the manifest
+	// (eg. "workspace" for local workspace members)
+	Replace map[string]string
 }
 
 // New returns a new resolver
@@ -78,6 +81,7 @@ func New(man *manifest.Manifest, platformLock manifest.PlatformLock) *Resolver {
 		IncludeDev:     true,
 		AlsoDownload:   false, // TODO: set to true when working properly
 		Providers:      make(map[string]providers.Provider, 2),
+		Replace:        make(map[string]string),
 		downloadWg:     sync.WaitGroup{},
 	}
 
@@ -233,9 +237,13 @@ func (r *Resolver) ResolveDependencies(ctx context.Context, dependencies []*mani
 }
 
 func (r *Resolver) resolveSingle(ctx context.Context, dependency *manifest.InterpretedDependency, root *manifest.DependencyLock) (*Resolved, error) {
-	provider, ok := r.Providers[dependency.Provider]
+	providerName := dependency.Provider
+	if replaced, ok := r.Replace[dependency.Name]; ok {
+		providerName = replaced
+	}
+	provider, ok := r.Providers[providerName]
 	if !ok {
-		return nil, fmt.Errorf("%s needs %s as install provider which is not supported", dependency.Name, dependency.Provider)
+		return nil, fmt.Errorf("%s needs %s as install provider which is not supported", dependency.Name, providerName)
 	}
 
 	request := r.providerRequest(dependency, root)
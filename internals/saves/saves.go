@@ -0,0 +1,257 @@
+// Package saves reads, imports and exports Minecraft savegames
+package saves
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Tnze/go-mc/nbt"
+	"github.com/minepkg/minepkg/internals/pack"
+)
+
+// ErrWorldExists is returned when importing a world that already exists
+var ErrWorldExists = errors.New("world already exists")
+
+// ErrNoWorld is returned if a zip file does not contain a level.dat
+var ErrNoWorld = errors.New("zip file does not contain a world (level.dat is missing)")
+
+var gameModes = map[int32]string{
+	0: "survival",
+	1: "creative",
+	2: "adventure",
+	3: "spectator",
+}
+
+// levelDat contains the fields of the `level.dat` we care about
+type levelDat struct {
+	Data struct {
+		LevelName  string
+		GameType   int32
+		LastPlayed int64
+		Hardcore   byte `nbt:"hardcore"`
+		Version    struct {
+			Name string
+		}
+	}
+}
+
+// World is a single savegame
+type World struct {
+	// Dir is the world directory
+	Dir string
+	// LevelName is the name shown in game (can differ from the directory name)
+	LevelName string
+	// GameMode is one of "survival", "creative", "adventure" or "spectator"
+	GameMode string
+	Hardcore bool
+	// LastPlayed is the last time this world was played
+	LastPlayed time.Time
+	// Version is the Minecraft version this world was last played with. Empty for very old worlds
+	Version string
+}
+
+// Name returns the directory name of the world
+func (w *World) Name() string {
+	return filepath.Base(w.Dir)
+}
+
+// ReadWorld reads the `level.dat` of the world in dir
+func ReadWorld(dir string) (*World, error) {
+	f, err := os.Open(filepath.Join(dir, "level.dat"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid level.dat: %w", err)
+	}
+	defer gz.Close()
+
+	level := levelDat{}
+	if err := nbt.NewDecoder(gz).Decode(&level); err != nil {
+		return nil, fmt.Errorf("invalid level.dat: %w", err)
+	}
+
+	gameMode, ok := gameModes[level.Data.GameType]
+	if !ok {
+		gameMode = "unknown"
+	}
+	return &World{
+		Dir:        dir,
+		LevelName:  level.Data.LevelName,
+		GameMode:   gameMode,
+		Hardcore:   level.Data.Hardcore == 1,
+		LastPlayed: time.Unix(0, level.Data.LastPlayed*int64(time.Millisecond)),
+		Version:    level.Data.Version.Name,
+	}, nil
+}
+
+// List returns all worlds in savesDir. The most recently played come first.
+// Directories without a readable `level.dat` are skipped
+func List(savesDir string) ([]*World, error) {
+	entries, err := ioutil.ReadDir(savesDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*World{}, nil
+		}
+		return nil, err
+	}
+
+	worlds := make([]*World, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		world, err := ReadWorld(filepath.Join(savesDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		worlds = append(worlds, world)
+	}
+	sort.Slice(worlds, func(a, b int) bool {
+		return worlds[a].LastPlayed.After(worlds[b].LastPlayed)
+	})
+	return worlds, nil
+}
+
+// worldRoot returns the directory (inside the zip) that contains the top most level.dat
+func worldRoot(files []*zip.File) (string, error) {
+	root := ""
+	depth := -1
+	for _, f := range files {
+		if path.Base(f.Name) != "level.dat" {
+			continue
+		}
+		dir := path.Dir(f.Name)
+		d := strings.Count(f.Name, "/")
+		if depth == -1 || d < depth {
+			root, depth = dir, d
+		}
+	}
+	if depth == -1 {
+		return "", ErrNoWorld
+	}
+	return root, nil
+}
+
+// Import extracts the world in the zip file at zipPath into savesDir. The world directory is named
+// name, or after the world directory in the zip (or the zip file) if name is empty.
+// Existing worlds are never overwritten
+func Import(zipPath string, savesDir string, name string) (string, error) {
+	r, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	root, err := worldRoot(r.File)
+	if err != nil {
+		return "", err
+	}
+	if name == "" {
+		name = path.Base(root)
+		if root == "." {
+			name = strings.TrimSuffix(filepath.Base(zipPath), filepath.Ext(zipPath))
+		}
+	}
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid world name %q", name)
+	}
+
+	target := filepath.Join(savesDir, name)
+	if _, err := os.Stat(target); err == nil {
+		return "", ErrWorldExists
+	}
+
+	prefix := ""
+	if root != "." {
+		prefix = root + "/"
+	}
+	if err := extractDir(r.File, prefix, target); err != nil {
+		os.RemoveAll(target)
+		return "", err
+	}
+	return name, nil
+}
+
+// ResetFromDir replaces the world in worldDir with a copy of src
+func ResetFromDir(src string, worldDir string) error {
+	if err := os.RemoveAll(worldDir); err != nil {
+		return err
+	}
+	return filepath.Walk(src, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		dest := filepath.Join(worldDir, rel)
+		if info.IsDir() {
+			return os.MkdirAll(dest, os.ModePerm)
+		}
+		content, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(dest, content, info.Mode().Perm())
+	})
+}
+
+// HasZipWorld returns true if the zip file (eg. a modpack) contains the directory prefix (eg. "saves/my-world/")
+func HasZipWorld(zipPath string, prefix string) (bool, error) {
+	r, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return false, err
+	}
+	defer r.Close()
+	for _, f := range r.File {
+		if strings.HasPrefix(f.Name, prefix) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ResetFromZip replaces the world in worldDir with the files below prefix in the zip file
+func ResetFromZip(zipPath string, prefix string, worldDir string) error {
+	r, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	if err := os.RemoveAll(worldDir); err != nil {
+		return err
+	}
+	return extractDir(r.File, prefix, worldDir)
+}
+
+// extractDir extracts all files below prefix to dest
+func extractDir(files []*zip.File, prefix string, dest string) error {
+	for _, f := range files {
+		if f.FileInfo().IsDir() || !strings.HasPrefix(f.Name, prefix) {
+			continue
+		}
+		name := strings.TrimPrefix(f.Name, prefix)
+		if err := pack.SanitizeExtractPath(name, dest); err != nil {
+			return err
+		}
+		if err := pack.ExtractFile(f, filepath.Join(dest, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package saves
+
+import (
+	"archive/zip"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Tnze/go-mc/nbt"
+)
+
+func writeLevelDat(t *testing.T, dir string, level *levelDat) {
+	t.Helper()
+	os.MkdirAll(dir, os.ModePerm)
+	f, err := os.Create(filepath.Join(dir, "level.dat"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	if err := nbt.NewEncoder(gz).Encode(*level); err != nil {
+		t.Fatal(err)
+	}
+	gz.Close()
+}
+
+func writeZip(t *testing.T, files map[string]string) string {
+	t.Helper()
+	zipPath := filepath.Join(t.TempDir(), "world.zip")
+	f, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	for name, content := range files {
+		w, _ := zw.Create(name)
+		w.Write([]byte(content))
+	}
+	zw.Close()
+	return zipPath
+}
+
+func TestReadWorld(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "world")
+	level := &levelDat{}
+	level.Data.Hardcore = 1
+	level.Data.LevelName = "Adventure Map"
+	level.Data.GameType = 2
+	level.Data.LastPlayed = 1600000000000
+	level.Data.Version.Name = "1.17.1"
+	writeLevelDat(t, dir, level)
+
+	world, err := ReadWorld(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if world.LevelName != "Adventure Map" || world.GameMode != "adventure" || world.Version != "1.17.1" || !world.Hardcore {
+		t.Errorf("unexpected world %+v", world)
+	}
+	if !world.LastPlayed.Equal(time.Unix(1600000000, 0)) {
+		t.Errorf("unexpected last played time %s", world.LastPlayed)
+	}
+}
+
+func TestImport(t *testing.T) {
+	savesDir := t.TempDir()
+
+	nested := writeZip(t, map[string]string{"my-world/level.dat": "x", "my-world/region/r.0.0.mca": "x"})
+	name, err := Import(nested, savesDir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "my-world" {
+		t.Errorf("unexpected name %s", name)
+	}
+	if _, err := os.Stat(filepath.Join(savesDir, "my-world", "region", "r.0.0.mca")); err != nil {
+		t.Error("world was not extracted")
+	}
+	if _, err := Import(nested, savesDir, ""); err != ErrWorldExists {
+		t.Errorf("expected ErrWorldExists, got %v", err)
+	}
+
+	flat := writeZip(t, map[string]string{"level.dat": "x"})
+	if name, err := Import(flat, savesDir, "flat"); err != nil || name != "flat" {
+		t.Errorf("could not import flat world: %s %v", name, err)
+	}
+
+	evil := writeZip(t, map[string]string{"evil/level.dat": "x", "evil/../../escaped": "x"})
+	if _, err := Import(evil, savesDir, ""); err == nil {
+		t.Error("expected zip slip to be rejected")
+	}
+
+	if _, err := Import(writeZip(t, map[string]string{"readme.txt": "x"}), savesDir, ""); err != ErrNoWorld {
+		t.Errorf("expected ErrNoWorld, got %v", err)
+	}
+}
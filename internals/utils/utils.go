@@ -1,15 +1,22 @@
 package utils
 
 import (
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"regexp"
 	"runtime"
 	"strings"
+
+	"github.com/stoewer/go-strcase"
 )
 
+// invalidNameChars matches everything that is not allowed in package names
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
 // lineMatch matches the git output
 var lineMatch = regexp.MustCompile("(.*)\r?\n?$")
 
@@ -52,3 +59,28 @@ func ReadJSONFile(filename string, i interface{}) error {
 	}
 	return json.Unmarshal(buf, i)
 }
+
+// PackageName converts any name (eg. "My Cool Pack!") to a valid package name ("my-cool-pack").
+// fallback is returned if nothing is left of name
+func PackageName(name string, fallback string) string {
+	name = strings.ToLower(strcase.KebabCase(name))
+	name = strings.Trim(invalidNameChars.ReplaceAllString(name, "-"), "-")
+	if name == "" {
+		return fallback
+	}
+	return name
+}
+
+// Sha256File returns the hex encoded sha256 hash of the file at path
+func Sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
@@ -0,0 +1,200 @@
+// Package workspace finds and reads workspaces. A workspace is a repository with multiple packages (members)
+// that is described by the `[workspace]` section in the minepkg.toml at its root
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/minepkg/minepkg/pkg/manifest"
+	"github.com/pelletier/go-toml"
+)
+
+// ErrCycle is returned if workspace members depend on each other in a cycle
+var ErrCycle = errors.New("workspace members have circular dependencies")
+
+// Member is a single package of the workspace
+type Member struct {
+	// Dir is the absolute path of the package directory
+	Dir      string
+	Manifest *manifest.Manifest
+}
+
+// Name returns the package name of the member
+func (m *Member) Name() string {
+	return m.Manifest.Package.Name
+}
+
+// Workspace is a repository with multiple packages
+type Workspace struct {
+	// Root is the directory containing the workspace minepkg.toml
+	Root     string
+	Manifest *manifest.Manifest
+	Members  []*Member
+}
+
+// Open reads the workspace in root and all of its members
+func Open(root string) (*Workspace, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	man, err := readManifest(root)
+	if err != nil {
+		return nil, err
+	}
+
+	ws := &Workspace{Root: root, Manifest: man}
+	seen := make(map[string]bool)
+	for _, pattern := range man.Workspace.Members {
+		matches, err := filepath.Glob(filepath.Join(root, filepath.FromSlash(pattern)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid workspace member %q: %w", pattern, err)
+		}
+		sort.Strings(matches)
+		for _, dir := range matches {
+			if _, err := os.Stat(filepath.Join(dir, "minepkg.toml")); err != nil {
+				continue
+			}
+			memberManifest, err := readManifest(dir)
+			if err != nil {
+				return nil, err
+			}
+			member := &Member{Dir: dir, Manifest: memberManifest}
+			if seen[member.Name()] {
+				return nil, fmt.Errorf("workspace has multiple members named %s", member.Name())
+			}
+			seen[member.Name()] = true
+			ws.Members = append(ws.Members, member)
+		}
+	}
+	return ws, nil
+}
+
+// Find returns the workspace that dir belongs to. dir can be the workspace root, a member directory
+// or any directory below them. nil is returned if dir is not part of a workspace
+func Find(dir string) (*Workspace, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for current := dir; ; {
+		if man, err := readManifest(current); err == nil && len(man.Workspace.Members) != 0 {
+			ws, err := Open(current)
+			if err != nil {
+				return nil, err
+			}
+			if current == dir || ws.MemberAt(dir) != nil {
+				return ws, nil
+			}
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return nil, nil
+		}
+		current = parent
+	}
+}
+
+// Member returns the member with the given package name or nil
+func (w *Workspace) Member(name string) *Member {
+	for _, member := range w.Members {
+		if member.Name() == name {
+			return member
+		}
+	}
+	return nil
+}
+
+// MemberAt returns the member that contains dir or nil
+func (w *Workspace) MemberAt(dir string) *Member {
+	for _, member := range w.Members {
+		rel, err := filepath.Rel(member.Dir, dir)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return member
+		}
+	}
+	return nil
+}
+
+// LockfilePath is the path of the lockfile that is shared by all members
+func (w *Workspace) LockfilePath() string {
+	return filepath.Join(w.Root, ".minepkg-lock.toml")
+}
+
+// Sorted returns the members in dependency order: every member comes after the members it depends on
+func (w *Workspace) Sorted() ([]*Member, error) {
+	return w.sort(w.Members)
+}
+
+// Dependencies returns the members that man depends on (directly or through other members) in dependency order
+func (w *Workspace) Dependencies(man *manifest.Manifest) ([]*Member, error) {
+	return w.sort(w.directDependencies(man))
+}
+
+// directDependencies returns the members that are dependencies of man
+func (w *Workspace) directDependencies(man *manifest.Manifest) []*Member {
+	names := make([]string, 0, len(man.Dependencies))
+	for name := range man.Dependencies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	members := make([]*Member, 0)
+	for _, name := range names {
+		if member := w.Member(name); member != nil {
+			members = append(members, member)
+		}
+	}
+	return members
+}
+
+// sort returns the given members and all members they depend on in dependency order
+func (w *Workspace) sort(members []*Member) ([]*Member, error) {
+	sorted := make([]*Member, 0, len(w.Members))
+	state := make(map[string]int) // 1 = visiting, 2 = done
+
+	var visit func(member *Member) error
+	visit = func(member *Member) error {
+		switch state[member.Name()] {
+		case 1:
+			return fmt.Errorf("%w (%s)", ErrCycle, member.Name())
+		case 2:
+			return nil
+		}
+		state[member.Name()] = 1
+		for _, dep := range w.directDependencies(member.Manifest) {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[member.Name()] = 2
+		sorted = append(sorted, member)
+		return nil
+	}
+
+	for _, member := range members {
+		if err := visit(member); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
+func readManifest(dir string) (*manifest.Manifest, error) {
+	raw, err := ioutil.ReadFile(filepath.Join(dir, "minepkg.toml"))
+	if err != nil {
+		return nil, err
+	}
+	man := &manifest.Manifest{}
+	if err := toml.Unmarshal(raw, man); err != nil {
+		return nil, fmt.Errorf("invalid minepkg.toml in %s: %w", dir, err)
+	}
+	return man, nil
+}
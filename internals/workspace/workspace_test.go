@@ -0,0 +1,130 @@
+package workspace
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeManifest(t *testing.T, dir string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "minepkg.toml"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testWorkspace(t *testing.T) string {
+	root := t.TempDir()
+	writeManifest(t, root, "[workspace]\nmembers = [\"mods/*\", \"pack\"]\n")
+	writeManifest(t, filepath.Join(root, "mods", "core"), `
+[package]
+type = "mod"
+name = "core"
+`)
+	writeManifest(t, filepath.Join(root, "mods", "addon"), `
+[package]
+type = "mod"
+name = "addon"
+
+[dependencies]
+core = "*"
+fabric = "*"
+`)
+	writeManifest(t, filepath.Join(root, "pack"), `
+[package]
+type = "modpack"
+name = "pack"
+
+[dependencies]
+addon = "*"
+`)
+	return root
+}
+
+func names(members []*Member) []string {
+	names := make([]string, len(members))
+	for i, member := range members {
+		names[i] = member.Name()
+	}
+	return names
+}
+
+func TestOpen(t *testing.T) {
+	ws, err := Open(testWorkspace(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := names(ws.Members)
+	if len(got) != 3 || got[0] != "addon" || got[1] != "core" || got[2] != "pack" {
+		t.Fatalf("unexpected members %v", got)
+	}
+}
+
+func TestFind(t *testing.T) {
+	root := testWorkspace(t)
+	src := filepath.Join(root, "mods", "addon", "src")
+	os.MkdirAll(src, os.ModePerm)
+
+	ws, err := Find(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ws == nil {
+		t.Fatal("expected to find the workspace")
+	}
+	if member := ws.MemberAt(src); member == nil || member.Name() != "addon" {
+		t.Fatalf("expected src to belong to addon, got %v", member)
+	}
+
+	// directories next to the members are not part of the workspace
+	other := filepath.Join(root, "docs")
+	os.MkdirAll(other, os.ModePerm)
+	if ws, err := Find(other); err != nil || ws != nil {
+		t.Fatalf("expected no workspace, got %v (%v)", ws, err)
+	}
+}
+
+func TestSorted(t *testing.T) {
+	ws, err := Open(testWorkspace(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sorted, err := ws.Sorted()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := names(sorted)
+	if len(got) != 3 || got[0] != "core" || got[1] != "addon" || got[2] != "pack" {
+		t.Fatalf("unexpected order %v", got)
+	}
+
+	deps, err := ws.Dependencies(ws.Member("pack").Manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = names(deps)
+	if len(got) != 2 || got[0] != "core" || got[1] != "addon" {
+		t.Fatalf("unexpected dependencies %v", got)
+	}
+}
+
+func TestSortedCycle(t *testing.T) {
+	root := t.TempDir()
+	writeManifest(t, root, "[workspace]\nmembers = [\"a\", \"b\"]\n")
+	writeManifest(t, filepath.Join(root, "a"), "[package]\nname = \"a\"\n[dependencies]\nb = \"*\"\n")
+	writeManifest(t, filepath.Join(root, "b"), "[package]\nname = \"b\"\n[dependencies]\na = \"*\"\n")
+
+	ws, err := Open(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ws.Sorted(); !errors.Is(err, ErrCycle) {
+		t.Fatalf("expected ErrCycle, got %v", err)
+	}
+}
@@ -29,3 +29,32 @@ func ExampleManifest_marshal() {
 
 	fmt.Println(manifest.String()) // or manifest.Buffer() to get it as a buffer
 }
+
+// Read a launch profile
+func ExampleLaunchProfile() {
+	raw := []byte(`
+	manifestVersion = 0
+	[package]
+	name="test-pack"
+	[launch.profiles.server]
+	ram = 4096
+	server = true
+`)
+	var man manifest.Manifest
+	toml.Unmarshal(raw, &man)
+	profile := man.Launch.Profiles["server"]
+	fmt.Println(profile.Ram, profile.Server)
+	// Output:
+	// 4096 true
+}
+
+// Get the install location of dependencies
+func ExampleDependencyLock_InstallPath() {
+	mod := &manifest.DependencyLock{Name: "test-mod", Version: "1.0.0", Type: manifest.DependencyLockTypeMod}
+	textures := &manifest.DependencyLock{Name: "test-textures", Version: "2.1.0", Type: manifest.DependencyLockTypeResourcepack}
+	fmt.Println(mod.InstallPath())
+	fmt.Println(textures.InstallPath())
+	// Output:
+	// mods/test-mod-1.0.0.jar
+	// resourcepacks/test-textures.zip
+}
@@ -21,6 +21,12 @@ var (
 	DependencyLockTypeMod = "mod"
 	// DependencyLockTypeModpack describes a modpack dependency
 	DependencyLockTypeModpack = "modpack"
+	// DependencyLockTypeResourcepack describes a resource pack dependency
+	DependencyLockTypeResourcepack = "resourcepack"
+	// DependencyLockTypeShaderpack describes a shader pack dependency
+	DependencyLockTypeShaderpack = "shaderpack"
+	// DependencyLockTypeDatapack describes a data pack dependency
+	DependencyLockTypeDatapack = "datapack"
 
 	PlatformFabric  = "fabric"
 	PlatformForge   = "forge"
@@ -41,6 +47,17 @@ type Lockfile struct {
 	Forge           *ForgeLock                 `toml:"forge,omitempty" json:"forge,omitempty"`
 	Vanilla         *VanillaLock               `toml:"vanilla,omitempty" json:"vanilla,omitempty"`
 	Dependencies    map[string]*DependencyLock `toml:"dependencies,omitempty" json:"dependencies,omitempty"`
+	// Workspace contains the locked requirements and dependencies of every workspace member.
+	// Only used in the shared lockfile of a workspace
+	Workspace map[string]*MemberLock `toml:"workspace,omitempty" json:"workspace,omitempty"`
+}
+
+// MemberLock describes the resolved requirements and dependencies of a single workspace member
+type MemberLock struct {
+	Fabric       *FabricLock                `toml:"fabric,omitempty" json:"fabric,omitempty"`
+	Forge        *ForgeLock                 `toml:"forge,omitempty" json:"forge,omitempty"`
+	Vanilla      *VanillaLock               `toml:"vanilla,omitempty" json:"vanilla,omitempty"`
+	Dependencies map[string]*DependencyLock `toml:"dependencies,omitempty" json:"dependencies,omitempty"`
 }
 
 // FabricLock describes resolved fabric requirements
@@ -104,15 +121,32 @@ type DependencyLock struct {
 	Dependend string `toml:"dependend" json:"dependend"`
 	// IsDev is true if this is a dev dependency
 	IsDev bool `toml:"isDev,omitempty" json:"isDev,omitempty"`
+	// Sha1 is only set for https dependencies that are pinned by hash
+	Sha1 string `toml:"sha1,omitempty" json:"sha1,omitempty"`
 }
 
-// FileExt returns ".jar" for mods and ".zip" for modpacks
+// FileExt returns ".jar" for mods and ".zip" for modpacks and resource, shader & data packs
 func (d *DependencyLock) FileExt() string {
-	ending := ".jar"
-	if d.Type == DependencyLockTypeModpack {
-		ending = ".zip"
+	switch d.Type {
+	case DependencyLockTypeModpack, DependencyLockTypeResourcepack, DependencyLockTypeShaderpack, DependencyLockTypeDatapack:
+		return ".zip"
 	}
-	return ending
+	return ".jar"
+}
+
+// InstallPath returns the path (relative to the minecraft directory) the dependency is installed to.
+// Packs are named without the version, so they stay selected in the game options after an update.
+// An empty string is returned for modpacks and data packs (they are installed into worlds)
+func (d *DependencyLock) InstallPath() string {
+	switch d.Type {
+	case DependencyLockTypeModpack, DependencyLockTypeDatapack:
+		return ""
+	case DependencyLockTypeResourcepack:
+		return "resourcepacks/" + d.Name + d.FileExt()
+	case DependencyLockTypeShaderpack:
+		return "shaderpacks/" + d.Name + d.FileExt()
+	}
+	return "mods/" + d.Filename()
 }
 
 // ID returns the a sha256 of "provider:name:version"
@@ -201,6 +235,41 @@ func (l *Lockfile) ClearDependencies() {
 	l.Dependencies = make(map[string]*DependencyLock)
 }
 
+// MemberLockfile returns a lockfile with the requirements and dependencies of the given workspace member
+// from this (shared workspace) lockfile. Members are locked independently, so they can use different versions
+func (l *Lockfile) MemberLockfile(member string) *Lockfile {
+	lock := NewLockfile()
+	memberLock, ok := l.Workspace[member]
+	if !ok {
+		return lock
+	}
+	lock.Fabric = memberLock.Fabric
+	lock.Forge = memberLock.Forge
+	lock.Vanilla = memberLock.Vanilla
+	for _, dep := range memberLock.Dependencies {
+		lock.AddDependency(dep)
+	}
+	return lock
+}
+
+// SetMember replaces the requirements and the dependencies of the given workspace member
+// with the ones from memberLock. Other members are not changed
+func (l *Lockfile) SetMember(member string, memberLock *Lockfile) {
+	if l.Workspace == nil {
+		l.Workspace = make(map[string]*MemberLock)
+	}
+	deps := make(map[string]*DependencyLock, len(memberLock.Dependencies))
+	for name, dep := range memberLock.Dependencies {
+		deps[name] = dep
+	}
+	l.Workspace[member] = &MemberLock{
+		Fabric:       memberLock.Fabric,
+		Forge:        memberLock.Forge,
+		Vanilla:      memberLock.Vanilla,
+		Dependencies: deps,
+	}
+}
+
 // NewLockfile returns a new lockfile
 func NewLockfile() *Lockfile {
 	manifest := Lockfile{LockfileVersion: LockfileVersion, Dependencies: make(map[string]*DependencyLock)}
@@ -0,0 +1,58 @@
+package manifest_test
+
+import (
+	"testing"
+
+	"github.com/minepkg/minepkg/pkg/manifest"
+	"github.com/pelletier/go-toml"
+)
+
+func memberLock(minecraft string, depVersion string) *manifest.Lockfile {
+	lock := manifest.NewLockfile()
+	lock.Fabric = &manifest.FabricLock{Minecraft: minecraft, FabricLoader: "0.11.3", Mapping: minecraft + "+build.1"}
+	lock.AddDependency(&manifest.DependencyLock{Name: "fabric", Version: depVersion, Type: manifest.DependencyLockTypeMod})
+	return lock
+}
+
+func TestLockfileMembers(t *testing.T) {
+	shared := manifest.NewLockfile()
+	shared.SetMember("old-mod", memberLock("1.16.5", "0.34.2"))
+	shared.SetMember("new-mod", memberLock("1.17.1", "0.40.1"))
+
+	// the shared lockfile is saved and read again
+	var read manifest.Lockfile
+	if err := toml.Unmarshal(shared.Buffer().Bytes(), &read); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		member    string
+		minecraft string
+		version   string
+	}{
+		{"old-mod", "1.16.5", "0.34.2"},
+		{"new-mod", "1.17.1", "0.40.1"},
+	}
+	for _, test := range tests {
+		lock := read.MemberLockfile(test.member)
+		if got := lock.MinecraftVersion(); got != test.minecraft {
+			t.Errorf("%s: minecraft is %s, want %s", test.member, got, test.minecraft)
+		}
+		dep, ok := lock.Dependencies["fabric"]
+		switch {
+		case !ok:
+			t.Errorf("%s: fabric dependency is missing", test.member)
+		case dep.Version != test.version:
+			t.Errorf("%s: fabric is %s, want %s", test.member, dep.Version, test.version)
+		}
+	}
+
+	// updating one member keeps the other one
+	read.SetMember("new-mod", manifest.NewLockfile())
+	if lock := read.MemberLockfile("old-mod"); lock.Dependencies["fabric"] == nil || lock.Fabric == nil {
+		t.Errorf("old-mod lost its lock after new-mod was updated")
+	}
+	if lock := read.MemberLockfile("new-mod"); len(lock.Dependencies) != 0 || lock.HasRequirements() {
+		t.Errorf("new-mod should have no lock anymore")
+	}
+}
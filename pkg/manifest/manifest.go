@@ -21,6 +21,12 @@ const (
 	TypeMod = "mod"
 	// TypeModpack indicates a package containing a list of mods (modpack)
 	TypeModpack = "modpack"
+	// TypeResourcepack indicates a package containing a resource pack (zip file)
+	TypeResourcepack = "resourcepack"
+	// TypeShaderpack indicates a package containing a shader pack (zip file)
+	TypeShaderpack = "shaderpack"
+	// TypeDatapack indicates a package containing a data pack (zip file)
+	TypeDatapack = "datapack"
 )
 
 // Manifest is a collection of data that describes a mod a modpack
@@ -29,7 +35,8 @@ type Manifest struct {
 	// This field is REQUIRED
 	ManifestVersion int `toml:"manifestVersion" comment:"Preview of the minepkg.toml format! Could break anytime!" json:"manifestVersion"`
 	Package         struct {
-		// Type should be one of `TypeMod` ("mod") or `TypeModpack` ("modpack")
+		// Type should be one of `TypeMod` ("mod"), `TypeModpack` ("modpack"), `TypeResourcepack` ("resourcepack"),
+		// `TypeShaderpack` ("shaderpack") or `TypeDatapack` ("datapack")
 		// this field is REQUIRED
 		Type string `toml:"type" json:"type"`
 		// Name is the name of the package. It may NOT include spaces. It may ONLY consist of
@@ -62,6 +69,7 @@ type Manifest struct {
 		BasedOn string `toml:"basedOn,omitempty" json:"basedOn,omitempty"`
 		// Savegame can be the name of the primary savegame on this modpack. Not applicable for other package types.
 		// This savegame will be used when launching this package via `minepkg try`.
+		// Data pack dependencies are only installed into this savegame (all savegames are used if it is not set).
 		// This should be the folder name of the savegame
 		Savegame string `toml:"build,omitempty" json:"build,omitempty"`
 	} `toml:"package" json:"package"`
@@ -88,7 +96,7 @@ type Manifest struct {
 		Forge  string `toml:"forge,omitempty" json:"forge,omitempty"`
 	} `toml:"requirements" comment:"These are global requirements" json:"requirements"`
 	// Dependencies lists runtime dependencies of this package
-	// this list can contain mods and modpacks
+	// this list can contain mods, modpacks and resource, shader & data packs
 	Dependencies `toml:"dependencies" json:"dependencies,omitempty"`
 	// Dev contains development & testing related options
 	Dev struct {
@@ -100,6 +108,42 @@ type Manifest struct {
 		// They should never be installed for published packages
 		Dependencies `toml:"dependencies,omitempty" json:"dependencies,omitempty"`
 	} `toml:"dev" json:"dev"`
+	// Launch contains options for launching this package locally
+	Launch struct {
+		// JvmArgs are additional arguments passed to the JVM (eg. "-Dfml.readTimeout=180")
+		JvmArgs []string `toml:"jvmArgs,omitempty" json:"jvmArgs,omitempty"`
+		// GameArgs are additional arguments passed to Minecraft
+		GameArgs []string `toml:"gameArgs,omitempty" json:"gameArgs,omitempty"`
+		// ReplaceDefaultJvmArgs removes the default JVM tuning flags (garbage collector settings etc.).
+		// Use this if `JvmArgs` contains a complete set of flags (like Aikar's flags)
+		ReplaceDefaultJvmArgs bool `toml:"replaceDefaultJvmArgs,omitempty" json:"replaceDefaultJvmArgs,omitempty"`
+		// Profiles are named sets of launch options. They can be used with `minepkg launch --profile <name>`
+		Profiles map[string]*LaunchProfile `toml:"profiles,omitempty" json:"profiles,omitempty"`
+	} `toml:"launch,omitempty" json:"launch,omitempty"`
+	// Workspace lists the packages of a repository with multiple packages.
+	// It is only used in the minepkg.toml at the root of the repository
+	Workspace struct {
+		// Members are the package directories relative to this manifest. Glob patterns like "mods/*" are allowed
+		Members []string `toml:"members,omitempty" json:"members,omitempty"`
+	} `toml:"workspace,omitempty" json:"workspace,omitempty"`
+	// Vars are custom variables that can be used in templated overwrites (files ending with `.tmpl`)
+	Vars map[string]string `toml:"vars,omitempty" json:"vars,omitempty"`
+}
+
+// LaunchProfile is a named set of launch options
+type LaunchProfile struct {
+	// Ram is the amount of RAM in MiB to start Minecraft with
+	Ram int `toml:"ram,omitempty" json:"ram,omitempty"`
+	// JvmArgs are additional arguments passed to the JVM
+	JvmArgs []string `toml:"jvmArgs,omitempty" json:"jvmArgs,omitempty"`
+	// Java is the Java runtime to use. Examples: 16-jre, 8-jre-openj9, system
+	Java string `toml:"java,omitempty" json:"java,omitempty"`
+	// Server starts a server instead of a client
+	Server bool `toml:"server,omitempty" json:"server,omitempty"`
+	// StartSave is a savegame to start after the client was launched
+	StartSave string `toml:"startSave,omitempty" json:"startSave,omitempty"`
+	// JoinServer is a server address to join after the client was launched
+	JoinServer string `toml:"joinServer,omitempty" json:"joinServer,omitempty"`
 }
 
 // Dependencies are the dependencies of a mod or modpack as a map